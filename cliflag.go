@@ -3,7 +3,10 @@ package main
 import (
 	"errors"
 	"flag"
+	"fmt"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/yinyin/go-literal-code-gen/external-filter/sqlschema"
 	"github.com/yinyin/go-literal-code-gen/literalcodegen"
@@ -15,12 +18,113 @@ var ErrInputFileRequired = errors.New("input file is required")
 // ErrOutputFileRequired indicates output file path is missing.
 var ErrOutputFileRequired = errors.New("output file is required")
 
-func parseCommandParam() (inputFilePath, outputFilePath string, genDoNotEdit bool, externalFilter literalcodegen.ExternalFilter, err error) {
+type variableFlags map[string]string
+
+func (v variableFlags) String() string {
+	var aux []string
+	for name, value := range v {
+		aux = append(aux, name+"="+value)
+	}
+	return strings.Join(aux, ",")
+}
+
+func (v variableFlags) Set(value string) error {
+	aux := strings.SplitN(value, "=", 2)
+	if (len(aux) != 2) || (strings.TrimSpace(aux[0]) == "") {
+		return fmt.Errorf("variable must be in KEY=VALUE form: %q", value)
+	}
+	v[strings.TrimSpace(aux[0])] = aux[1]
+	return nil
+}
+
+type languageFilterFlags []string
+
+func (v *languageFilterFlags) String() string {
+	return strings.Join(*v, ",")
+}
+
+func (v *languageFilterFlags) Set(value string) error {
+	for _, lang := range strings.Split(value, ",") {
+		if lang = strings.TrimSpace(lang); lang != "" {
+			*v = append(*v, lang)
+		}
+	}
+	return nil
+}
+
+type filterCommandFlags map[string][]string
+
+func (v filterCommandFlags) String() string {
+	var aux []string
+	for name, args := range v {
+		aux = append(aux, name+"="+strings.Join(args, " "))
+	}
+	return strings.Join(aux, ",")
+}
+
+func (v filterCommandFlags) Set(value string) error {
+	aux := strings.SplitN(value, "=", 2)
+	if (len(aux) != 2) || (strings.TrimSpace(aux[0]) == "") {
+		return fmt.Errorf("filter command must be in NAME=COMMAND form: %q", value)
+	}
+	args := strings.Fields(aux[1])
+	if len(args) == 0 {
+		return fmt.Errorf("command of filter command is empty: %q", value)
+	}
+	v[strings.TrimSpace(aux[0])] = args
+	return nil
+}
+
+func applyFilterCommandFlags(filterCommands filterCommandFlags, timeout time.Duration, cacheDir string) (err error) {
+	for name, args := range filterCommands {
+		if err = literalcodegen.RegisterFilterCommand(name, args, timeout); nil != err {
+			return
+		}
+	}
+	if cacheDir != "" {
+		if cacheDir, err = filepath.Abs(cacheDir); nil != err {
+			return
+		}
+		literalcodegen.SetFilterCommandCacheDir(cacheDir)
+	}
+	return nil
+}
+
+func applyLanguageFilterFlags(enableLangFilters, disableLangFilters languageFilterFlags) (err error) {
+	for _, lang := range enableLangFilters {
+		if err = literalcodegen.EnableLanguageFilter(lang); nil != err {
+			return
+		}
+	}
+	for _, lang := range disableLangFilters {
+		if err = literalcodegen.DisableLanguageFilter(lang); nil != err {
+			return
+		}
+	}
+	return nil
+}
+
+func parseCommandParam() (inputFilePath, outputFilePath string, genDoNotEdit bool, externalFilter literalcodegen.ExternalFilter, variables map[string]string, err error) {
 	var useSQLSchemaFilter bool
+	var sqlSchemaTransactionScope string
+	var sqlSchemaContextPassing bool
+	var enableLangFilters, disableLangFilters languageFilterFlags
+	var filterCommandTimeout time.Duration
+	var filterCommandCacheDir string
+	filterCommands := make(filterCommandFlags)
+	variables = make(variableFlags)
 	flag.StringVar(&inputFilePath, "in", "", "path to input file")
 	flag.StringVar(&outputFilePath, "out", "", "path to output file")
 	flag.BoolVar(&genDoNotEdit, "do-not-edit", false, "generate DO-NOT-EDIT code line")
 	flag.BoolVar(&useSQLSchemaFilter, "sqlschema", false, "enable SQL schema filter")
+	flag.StringVar(&sqlSchemaTransactionScope, "sqlschema-tx", sqlschema.TransactionScopeNone, "run schema modifications of SQL schema filter in transaction (step or upgrade)")
+	flag.BoolVar(&sqlSchemaContextPassing, "sqlschema-ctx", false, "make routines of SQL schema filter take context and run with SchemaExecutor")
+	flag.Var((variableFlags)(variables), "var", "generation-time variable in KEY=VALUE form (repeatable)")
+	flag.Var(&enableLangFilters, "enable-lang-filter", "enable registered language filters (comma separated, registered: "+strings.Join(literalcodegen.RegisteredLanguageFilters(), ",")+")")
+	flag.Var(&disableLangFilters, "disable-lang-filter", "disable registered language filters (comma separated)")
+	flag.Var(filterCommands, "filter-command", "local command for filter-command option in NAME=COMMAND form (repeatable)")
+	flag.DurationVar(&filterCommandTimeout, "filter-command-timeout", literalcodegen.DefaultFilterCommandTimeout, "timeout of filter commands")
+	flag.StringVar(&filterCommandCacheDir, "filter-command-cache", "", "folder to cache output of filter commands")
 	flag.Parse()
 	if inputFilePath == "" {
 		err = ErrInputFileRequired
@@ -36,8 +140,20 @@ func parseCommandParam() (inputFilePath, outputFilePath string, genDoNotEdit boo
 	if outputFilePath, err = filepath.Abs(outputFilePath); nil != err {
 		return
 	}
+	if err = applyLanguageFilterFlags(enableLangFilters, disableLangFilters); nil != err {
+		return
+	}
+	if err = applyFilterCommandFlags(filterCommands, filterCommandTimeout, filterCommandCacheDir); nil != err {
+		return
+	}
 	if useSQLSchemaFilter {
-		externalFilter = sqlschema.NewCodeGenerateFilter()
+		if err = sqlschema.CheckTransactionScope(sqlSchemaTransactionScope); nil != err {
+			return
+		}
+		sqlSchemaFilter := sqlschema.NewCodeGenerateFilter()
+		sqlSchemaFilter.TransactionScope = sqlSchemaTransactionScope
+		sqlSchemaFilter.ContextPassing = sqlSchemaContextPassing
+		externalFilter = sqlSchemaFilter
 	}
 	err = nil
 	return
@@ -0,0 +1,168 @@
+package sqlschema
+
+import (
+	"os"
+	"strings"
+
+	"github.com/yinyin/go-literal-code-gen/literalcodegen"
+)
+
+const (
+	schemaExecutorSymbol         = "SchemaExecutor"
+	schemaManagerInterfaceSymbol = "SchemaManager"
+	schemaManagerCtorSymbol      = "NewSchemaManager"
+)
+
+// withCtxParam prepend context parameter to given parameter declarations
+// when routines take context as argument.
+func (filter *CodeGenerateFilter) withCtxParam(params string) string {
+	if !filter.ContextPassing {
+		return params
+	}
+	if params == "" {
+		return "ctx context.Context"
+	}
+	return "ctx context.Context, " + params
+}
+
+// withCtxArg prepend context argument to given arguments when routines take
+// context as argument.
+func (filter *CodeGenerateFilter) withCtxArg(args string) string {
+	if !filter.ContextPassing {
+		return args
+	}
+	if args == "" {
+		return "ctx"
+	}
+	return "ctx, " + args
+}
+
+// leadingCtxArg return context argument with trailing separator for
+// argument list which continues with other arguments.
+func (filter *CodeGenerateFilter) leadingCtxArg() string {
+	if !filter.ContextPassing {
+		return ""
+	}
+	return "ctx, "
+}
+
+// ctxExpr return expression of context in routines.
+func (filter *CodeGenerateFilter) ctxExpr() string {
+	if filter.ContextPassing {
+		return "ctx"
+	}
+	return "m.ctx"
+}
+
+func (filter *CodeGenerateFilter) schemaRevisionSymbol() string {
+	if filter.ContextPassing {
+		return "SchemaRevision"
+	}
+	return "schemaRevision"
+}
+
+// schemaRevisionRecordTypeSymbol return type symbol of revision record of
+// builder table used in exported symbols. Record type is exported when
+// routines take context so that SchemaManager can be implemented outside of
+// package. The unexported symbol is kept as type alias for routines code.
+func (filter *CodeGenerateFilter) schemaRevisionRecordTypeSymbol(prop *tableProperty) string {
+	if filter.ContextPassing {
+		return "SchemaRevisionOf" + prop.SymbolName
+	}
+	return prop.schemaRevisionRecordStructSymbol()
+}
+
+// generateSchemaRevisionRecordAccessors generate constructor and accessors
+// of exported revision record type so that records can be built and
+// inspected outside of package.
+func (filter *CodeGenerateFilter) generateSchemaRevisionRecordAccessors(fp *os.File, prop *tableProperty) (err error) {
+	typeSymbol := filter.schemaRevisionRecordTypeSymbol(prop)
+	params := append([]string{"currentRev int32"}, prop.Entry.Parameters...)
+	args := parametersToArguments(params)
+	var fieldsCode string
+	for _, arg := range args {
+		fieldsCode += "\t\t" + arg + ": " + arg + ",\n"
+	}
+	if _, err = fp.WriteString("// New" + typeSymbol + " create revision record of " + prop.MetaName + " table.\n" +
+		"func New" + typeSymbol + "(" + strings.Join(params, ", ") + ") *" + typeSymbol + " {\n" +
+		"\treturn &" + typeSymbol + "{\n" +
+		fieldsCode +
+		"\t}\n" +
+		"}\n\n" +
+		"// CurrentRevision return current schema revision of record.\n" +
+		"func (r *" + typeSymbol + ") CurrentRevision() int32 {\n" +
+		"\treturn r.currentRev\n" +
+		"}\n\n"); nil != err {
+		return
+	}
+	for _, param := range prop.Entry.Parameters {
+		aux := strings.SplitN(strings.TrimSpace(param), " ", 2)
+		if len(aux) != 2 {
+			continue
+		}
+		name := aux[0]
+		accessorSymbol := strings.ToUpper(name[:1]) + name[1:]
+		if _, err = fp.WriteString("// " + accessorSymbol + " return " + name + " parameter of record.\n" +
+			"func (r *" + typeSymbol + ") " + accessorSymbol + "() " + strings.TrimSpace(aux[1]) + " {\n" +
+			"\treturn r." + name + "\n" +
+			"}\n\n"); nil != err {
+			return
+		}
+	}
+	return
+}
+
+func (filter *CodeGenerateFilter) generateSchemaExecutorInterface(fp *os.File) (err error) {
+	_, err = fp.WriteString("// " + schemaExecutorSymbol + " runs SQL statements of schema manager. It is\n" +
+		"// implemented by *sql.DB, *sql.Tx and *sql.Conn.\n" +
+		"type " + schemaExecutorSymbol + " interface {\n" +
+		"\tExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)\n" +
+		"\tQueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)\n" +
+		"\tQueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row\n" +
+		"}\n\n")
+	return
+}
+
+// managerRoutineSignatures return signatures of exported routines of
+// schema manager.
+func (filter *CodeGenerateFilter) managerRoutineSignatures() (signatures []string) {
+	signatures = append(signatures, "FetchSchemaRevision("+filter.withCtxParam("")+") (schemaRev *"+filter.schemaRevisionSymbol()+", err error)")
+	for _, prop := range filter.TableProperties {
+		switch prop.Entry.TranslationMode {
+		case literalcodegen.TranslateAsConst:
+			signatures = append(signatures, prop.upgradeRoutineSymbol()+"("+filter.withCtxParam("currentRev int32")+") (schemaChanged bool, err error)")
+			if prop.hasRollbackEntries() {
+				signatures = append(signatures, prop.downgradeRoutineSymbol()+"("+filter.withCtxParam("currentRev, targetRev int32")+") (schemaChanged bool, err error)")
+			}
+		case literalcodegen.TranslateAsBuilder:
+			signatures = append(signatures, prop.upgradeWithRevisionRecordsRoutineSymbol()+"("+filter.withCtxParam("revisionRecords []*"+filter.schemaRevisionRecordTypeSymbol(prop))+") (schemaChanged bool, err error)")
+			if prop.hasRollbackEntries() {
+				signatures = append(signatures, prop.downgradeWithRevisionRecordsRoutineSymbol()+"("+filter.withCtxParam("revisionRecords []*"+filter.schemaRevisionRecordTypeSymbol(prop)+", targetRev int32")+") (schemaChanged bool, err error)")
+			}
+		}
+	}
+	return
+}
+
+// generateSchemaManagerInterface generate exported interface of schema
+// manager and the constructor.
+func (filter *CodeGenerateFilter) generateSchemaManagerInterface(fp *os.File) (err error) {
+	if !filter.ContextPassing {
+		return nil
+	}
+	signatures := filter.managerRoutineSignatures()
+	if _, err = fp.WriteString("// " + schemaManagerInterfaceSymbol + " fetches and upgrades schema revisions of tables.\n" +
+		"type " + schemaManagerInterfaceSymbol + " interface {\n" +
+		"\t" + strings.Join(signatures, "\n\t") + "\n" +
+		"}\n\n"); nil != err {
+		return
+	}
+	_, err = fp.WriteString("// " + schemaManagerCtorSymbol + " create schema manager which runs statements with given executor.\n" +
+		"func " + schemaManagerCtorSymbol + "(conn " + schemaExecutorSymbol + ", referenceTableName string) " + schemaManagerInterfaceSymbol + " {\n" +
+		"\treturn &schemaManager{\n" +
+		"\t\treferenceTableName: referenceTableName,\n" +
+		"\t\tconn:               conn,\n" +
+		"\t}\n" +
+		"}\n\n")
+	return
+}
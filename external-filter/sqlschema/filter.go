@@ -15,9 +15,10 @@ import (
 
 var tablePropTitleTrap *regexp.Regexp
 var migrateRevTitleTrap *regexp.Regexp
+var rollbackRevTitleTrap *regexp.Regexp
 
 func compileTrapRegexps() (err error) {
-	if (nil != tablePropTitleTrap) && (nil != migrateRevTitleTrap) {
+	if (nil != tablePropTitleTrap) && (nil != migrateRevTitleTrap) && (nil != rollbackRevTitleTrap) {
 		return nil
 	}
 	if tablePropTitleTrap, err = regexp.Compile(`([a-zA-Z0-9_]+)\s+\(([a-zA-Z0-9-_]+)\)\s+r\.\s*([0-9]+)`); nil != err {
@@ -28,6 +29,10 @@ func compileTrapRegexps() (err error) {
 		log.Printf("ERR: failed on compiling regular expression for trapping migration revision: %v", err)
 		return
 	}
+	if rollbackRevTitleTrap, err = regexp.Compile(`From\s+r\.\s*([0-9]+)`); nil != err {
+		log.Printf("ERR: failed on compiling regular expression for trapping rollback revision: %v", err)
+		return
+	}
 	return
 }
 
@@ -41,6 +46,25 @@ func parametersToArguments(params []string) (args []string) {
 	return
 }
 
+// sqlStatementsExpression return expression of SQL statement slice for
+// given symbol expression of entry.
+func sqlStatementsExpression(entry *literalcodegen.LiteralEntry, symbolExpr string) string {
+	if entry.IsSplitContent() {
+		return symbolExpr
+	}
+	return "[]string{" + symbolExpr + "}"
+}
+
+// customSchemaUpdateCode return Go code of migration or rollback entry which
+// runs before revision update.
+func customSchemaUpdateCode(entry *literalcodegen.LiteralEntry) (code string, err error) {
+	customCode, err := entry.FilteredContent()
+	if nil != err {
+		return
+	}
+	return strings.TrimSpace(strings.Join(customCode, "\n")) + "\n", nil
+}
+
 func writeTrimmedCodeLine(fp *os.File, codeLine string) (err error) {
 	if codeLine = strings.TrimSpace(codeLine); codeLine == "" {
 		return nil
@@ -63,6 +87,15 @@ type CodeGenerateFilter struct {
 	TableProperties []*tableProperty
 
 	GeneratedTODOs int
+
+	// TransactionScope is the scope of transaction for schema modifications
+	TransactionScope string
+
+	// ContextPassing makes routines take context as argument and run
+	// statements with SchemaExecutor
+	ContextPassing bool
+
+	useMetaTableExistedRoutine bool
 }
 
 // NewCodeGenerateFilter create an instance of CodeGenerateFilter
@@ -80,7 +113,7 @@ func (filter *CodeGenerateFilter) PreCodeGenerate(entries []*literalcodegen.Lite
 		return
 	}
 	for _, entry := range entries {
-		if entry.LevelDepth != 0 {
+		if (entry.LevelDepth != 0) || entry.IsVariant() {
 			continue
 		}
 		if prop := newTablePropertyFromTitle1(entry); nil != prop {
@@ -101,8 +134,7 @@ func (filter *CodeGenerateFilter) cacheBaseRoutines() (err error) {
 	if len(filter.TableProperties) < 1 {
 		return nil
 	}
-	metaTableProp := filter.TableProperties[0]
-	if filter.FetchRevisionPrepareCodeLines, filter.FetchRevisionCodeLines, filter.UpdateRevisionCodeLines, err = metaTableProp.fetchRoutines(); nil != err {
+	if filter.FetchRevisionPrepareCodeLines, filter.FetchRevisionCodeLines, filter.UpdateRevisionCodeLines, err = filter.baseRoutines(); nil != err {
 		return err
 	}
 	filter.FetchRevisionCodeTpl, err = interpolatetext.NewTextMapInterpolationSlice(filter.FetchRevisionCodeLines)
@@ -147,7 +179,7 @@ func (filter *CodeGenerateFilter) generateSchemaRevisionStruct(fp *os.File) (err
 			return
 		}
 	}
-	if _, err = fp.WriteString("type schemaRevision struct {\n"); nil != err {
+	if _, err = fp.WriteString("type " + filter.schemaRevisionSymbol() + " struct {\n"); nil != err {
 		return
 	}
 	for _, prop := range filter.TableProperties {
@@ -158,7 +190,7 @@ func (filter *CodeGenerateFilter) generateSchemaRevisionStruct(fp *os.File) (err
 		case literalcodegen.TranslateAsConst:
 			codeLine = "\t" + prop.SymbolName + " int32\n"
 		case literalcodegen.TranslateAsBuilder:
-			codeLine = "\t" + prop.SymbolName + " []*" + prop.schemaRevisionRecordStructSymbol() + "\n"
+			codeLine = "\t" + prop.SymbolName + " []*" + filter.schemaRevisionRecordTypeSymbol(prop) + "\n"
 		default:
 			codeLine = fmt.Sprintf("\t// TODO: unknown translation mode %v for symbol [%v]\n", prop.Entry.TranslationMode, prop.SymbolName)
 		}
@@ -171,7 +203,7 @@ func (filter *CodeGenerateFilter) generateSchemaRevisionStruct(fp *os.File) (err
 	if _, err = fp.WriteString("}\n\n"); nil != err {
 		return
 	}
-	if _, err = fp.WriteString("func (rev *schemaRevision) IsUpToDate() bool {\n"); nil != err {
+	if _, err = fp.WriteString("func (rev *" + filter.schemaRevisionSymbol() + ") IsUpToDate() bool {\n"); nil != err {
 		return
 	}
 	for _, prop := range filter.TableProperties {
@@ -210,14 +242,33 @@ func (filter *CodeGenerateFilter) hasConstTableProperty() bool {
 }
 
 func (filter *CodeGenerateFilter) generateSchemaManager(fp *os.File) (err error) {
+	connType := "*sql.DB"
+	ctxField := "\tctx context.Context\n"
+	if filter.ContextPassing {
+		if err = filter.generateSchemaExecutorInterface(fp); nil != err {
+			return
+		}
+		connType = schemaExecutorSymbol
+		ctxField = ""
+	} else if filter.transactional() {
+		if err = filter.generateSchemaConnInterface(fp); nil != err {
+			return
+		}
+		connType = "schemaConn"
+	}
 	if _, err = fp.WriteString("type schemaManager struct {\n" +
 		"\treferenceTableName string\n" +
-		"\tctx context.Context\n" +
-		"\tconn *sql.DB\n" +
+		ctxField +
+		"\tconn " + connType + "\n" +
 		"}\n\n"); nil != err {
 		return
 	}
-	if _, err = fp.WriteString("func (m *schemaManager) FetchSchemaRevision() (schemaRev *schemaRevision, err error) {\n"); nil != err {
+	if filter.transactional() {
+		if err = filter.generateWithSchemaTxRoutine(fp); nil != err {
+			return
+		}
+	}
+	if _, err = fp.WriteString("func (m *schemaManager) FetchSchemaRevision(" + filter.withCtxParam("") + ") (schemaRev *" + filter.schemaRevisionSymbol() + ", err error) {\n"); nil != err {
 		return
 	}
 	if filter.hasConstTableProperty() {
@@ -226,7 +277,7 @@ func (filter *CodeGenerateFilter) generateSchemaManager(fp *os.File) (err error)
 				return
 			}
 		}
-		if _, err = fp.WriteString("\tschemaRev = &schemaRevision{}\n"); nil != err {
+		if _, err = fp.WriteString("\tschemaRev = &" + filter.schemaRevisionSymbol() + "{}\n"); nil != err {
 			return
 		}
 		for _, prop := range filter.TableProperties {
@@ -247,7 +298,7 @@ func (filter *CodeGenerateFilter) generateSchemaManager(fp *os.File) (err error)
 			}
 		}
 	} else {
-		if _, err = fp.WriteString("\tschemaRev = &schemaRevision{}\n"); nil != err {
+		if _, err = fp.WriteString("\tschemaRev = &" + filter.schemaRevisionSymbol() + "{}\n"); nil != err {
 			return
 		}
 	}
@@ -255,7 +306,7 @@ func (filter *CodeGenerateFilter) generateSchemaManager(fp *os.File) (err error)
 		var codeLine string
 		switch prop.Entry.TranslationMode {
 		case literalcodegen.TranslateAsBuilder:
-			codeLine = "\tif schemaRev." + prop.SymbolName + ", err = m." + prop.fetchSchemaRevisionRecordsSymbol() + "(); nil != err {\n" +
+			codeLine = "\tif schemaRev." + prop.SymbolName + ", err = m." + prop.fetchSchemaRevisionRecordsSymbol() + "(" + filter.withCtxArg("") + "); nil != err {\n" +
 				"\t\treturn nil, err\n" +
 				"\t}\n"
 		}
@@ -270,7 +321,7 @@ func (filter *CodeGenerateFilter) generateSchemaManager(fp *os.File) (err error)
 		return
 	}
 	if filter.hasConstTableProperty() {
-		if _, err = fp.WriteString("func (m *schemaManager) updateBaseTableSchemaRevision(key string, rev int32) (err error) {\n"); nil != err {
+		if _, err = fp.WriteString("func (m *schemaManager) updateBaseTableSchemaRevision(" + filter.withCtxParam("key string, rev int32") + ") (err error) {\n"); nil != err {
 			return
 		}
 		for _, codeLine := range filter.UpdateRevisionCodeLines {
@@ -282,11 +333,15 @@ func (filter *CodeGenerateFilter) generateSchemaManager(fp *os.File) (err error)
 			"}\n\n"); nil != err {
 			return
 		}
-		if _, err = fp.WriteString("func (m *schemaManager) execBaseSchemaModification(sqlStmt, schemaMetaKey string, targetRev int32) (err error) {\n" +
-			"\tif _, err = m.conn.Exec(sqlStmt); nil != err {\n" +
-			"\t\treturn\n" +
+		if _, err = fp.WriteString("func (m *schemaManager) execBaseSchemaModification(" + filter.withCtxParam("sqlStmts []string, schemaMetaKey string, targetRev int32") + ") (err error) {\n" +
+			filter.stepTransactionOpenCode() +
+			"\tfor _, sqlStmt := range sqlStmts {\n" +
+			"\t\tif _, err = m.conn.ExecContext(" + filter.ctxExpr() + ", sqlStmt); nil != err {\n" +
+			"\t\t\treturn\n" +
+			"\t\t}\n" +
 			"\t}\n" +
-			"\treturn m.updateBaseTableSchemaRevision(schemaMetaKey, targetRev)\n" +
+			"\treturn m.updateBaseTableSchemaRevision(" + filter.withCtxArg("schemaMetaKey, targetRev") + ")\n" +
+			filter.stepTransactionCloseCode() +
 			"}\n\n"); nil != err {
 			return
 		}
@@ -305,16 +360,24 @@ func (filter *CodeGenerateFilter) generateSchemaManager(fp *os.File) (err error)
 			}
 		}
 	}
+	if filter.useMetaTableExistedRoutine {
+		if _, err = fp.WriteString(filter.revisionRoutineTemplate().tableExistedRoutineCode(filter, filter.metaTableName())); nil != err {
+			return
+		}
+	}
 	return nil
 }
 
 func (filter *CodeGenerateFilter) generateBaseSchemaUpgradeRoutine(fp *os.File, prop *tableProperty) (err error) {
-	if _, err = fp.WriteString("func (m *schemaManager) " + prop.upgradeRoutineSymbol() + "(currentRev int32) (schemaChanged bool, err error) {\n" +
+	if err = filter.generateTransactionWrapperRoutine(fp, prop.upgradeRoutineSymbol(), "currentRev int32", "currentRev"); nil != err {
+		return
+	}
+	if _, err = fp.WriteString("func (m *schemaManager) " + filter.stepsRoutineSymbol(prop.upgradeRoutineSymbol()) + "(" + filter.withCtxParam("currentRev int32") + ") (schemaChanged bool, err error) {\n" +
 		"\tswitch currentRev {\n" +
 		"\tcase " + prop.currentRevisionSymbol() + ":\n" +
 		"\t\treturn false, nil\n" +
 		"\tcase 0:\n" +
-		"\t\tif err = m.execBaseSchemaModification(" + prop.sqlCreateSymbol() + ", " + prop.metaKeySymbol() + ", " + prop.currentRevisionSymbol() + "); nil == err {\n" +
+		"\t\tif err = m.execBaseSchemaModification(" + filter.withCtxArg(sqlStatementsExpression(prop.Entry, prop.sqlCreateSymbol())) + ", " + prop.metaKeySymbol() + ", " + prop.currentRevisionSymbol() + "); nil == err {\n" +
 		"\t\t\treturn true, nil\n" +
 		"\t\t}\n"); nil != err {
 		return
@@ -324,8 +387,22 @@ func (filter *CodeGenerateFilter) generateBaseSchemaUpgradeRoutine(fp *os.File,
 		if nil == entry {
 			continue
 		}
+		var schemaUpdateCustomCode string
+		var schemaUpdateInvokeCode string
+		if migrateEntrySymbol := prop.migrateEntrySymbol(entry, int32(sourceRev)); migrateEntrySymbol != "" {
+			schemaUpdateInvokeCode = "execBaseSchemaModification(" + filter.withCtxArg(sqlStatementsExpression(entry, migrateEntrySymbol)) + ", "
+		} else {
+			if entry.LanguageType == "go" {
+				if schemaUpdateCustomCode, err = customSchemaUpdateCode(entry); nil != err {
+					return
+				}
+			}
+			schemaUpdateInvokeCode = "updateBaseTableSchemaRevision(" + filter.leadingCtxArg()
+		}
+		leadingCode, invokeExpr := filter.customStepCode(schemaUpdateCustomCode, schemaUpdateInvokeCode+prop.metaKeySymbol()+", "+strconv.FormatInt(int64(sourceRev+1), 10)+")")
 		if _, err = fp.WriteString("\tcase " + strconv.FormatInt(int64(sourceRev), 10) + ":\n" +
-			"\t\tif err = m.execBaseSchemaModification(" + prop.migrateEntrySymbol(entry, int32(sourceRev)) + ", " + prop.metaKeySymbol() + ", " + strconv.FormatInt(int64(sourceRev+1), 10) + "); nil == err {\n" +
+			leadingCode +
+			"\t\tif err = " + invokeExpr + "; nil == err {\n" +
 			"\t\t\tschemaChanged = true\n"); nil != err {
 			return
 		}
@@ -353,8 +430,144 @@ func (filter *CodeGenerateFilter) generateBaseSchemaUpgradeRoutine(fp *os.File,
 	return nil
 }
 
+// missingRollbackRevisionsText return comma separated revisions which cannot
+// be rolled back.
+func (prop *tableProperty) missingRollbackRevisionsText() string {
+	var revs []string
+	for idx, entry := range prop.RollbackEntries {
+		if (nil == entry) || ((prop.rollbackEntrySymbol(entry, int32(idx)) == "") && (entry.LanguageType != "go")) {
+			revs = append(revs, strconv.FormatInt(int64(idx+1), 10))
+		}
+	}
+	return strings.Join(revs, ", ")
+}
+
+func (filter *CodeGenerateFilter) writeSchemaDowngradeRoutineHead(fp *os.File, prop *tableProperty, routineSymbol, extraParams string) (err error) {
+	if _, err = fp.WriteString("func (m *schemaManager) " + routineSymbol + "(" + filter.withCtxParam("currentRev, targetRev int32"+extraParams) + ") (schemaChanged bool, err error) {\n" +
+		"\tif (targetRev < 0) || (targetRev > currentRev) || (currentRev > " + prop.currentRevisionSymbol() + ") {\n" +
+		"\t\treturn false, fmt.Errorf(\"cannot downgrade " + prop.MetaName + " schema from revision %d to %d\", currentRev, targetRev)\n" +
+		"\t}\n"); nil != err {
+		return
+	}
+	if missingRevs := prop.missingRollbackRevisionsText(); missingRevs != "" {
+		if _, err = fp.WriteString("\tfor rev := currentRev; rev > targetRev; rev-- {\n" +
+			"\t\tswitch rev {\n" +
+			"\t\tcase " + missingRevs + ":\n" +
+			"\t\t\treturn false, fmt.Errorf(\"rollback from " + prop.MetaName + " schema revision %d is not available\", rev)\n" +
+			"\t\t}\n" +
+			"\t}\n"); nil != err {
+			return
+		}
+	}
+	_, err = fp.WriteString("\tfor rev := currentRev; rev > targetRev; rev-- {\n" +
+		"\t\tswitch rev {\n")
+	return
+}
+
+func (filter *CodeGenerateFilter) writeSchemaDowngradeRoutineTail(fp *os.File) (err error) {
+	_, err = fp.WriteString("\t\t}\n" +
+		"\t\tif nil != err {\n" +
+		"\t\t\treturn\n" +
+		"\t\t}\n" +
+		"\t\tschemaChanged = true\n" +
+		"\t}\n" +
+		"\treturn\n" +
+		"}\n\n")
+	return
+}
+
+func (filter *CodeGenerateFilter) generateBaseSchemaDowngradeRoutine(fp *os.File, prop *tableProperty) (err error) {
+	if err = filter.generateTransactionWrapperRoutine(fp, prop.downgradeRoutineSymbol(), "currentRev, targetRev int32", "currentRev, targetRev"); nil != err {
+		return
+	}
+	if err = filter.writeSchemaDowngradeRoutineHead(fp, prop, filter.stepsRoutineSymbol(prop.downgradeRoutineSymbol()), ""); nil != err {
+		return
+	}
+	for idx := len(prop.RollbackEntries) - 1; idx >= 0; idx-- {
+		entry := prop.RollbackEntries[idx]
+		if nil == entry {
+			continue
+		}
+		var schemaUpdateCustomCode string
+		var schemaUpdateInvokeCode string
+		if rollbackEntrySymbol := prop.rollbackEntrySymbol(entry, int32(idx)); rollbackEntrySymbol != "" {
+			schemaUpdateInvokeCode = "execBaseSchemaModification(" + filter.withCtxArg(sqlStatementsExpression(entry, rollbackEntrySymbol)) + ", "
+		} else if entry.LanguageType == "go" {
+			if schemaUpdateCustomCode, err = customSchemaUpdateCode(entry); nil != err {
+				return
+			}
+			schemaUpdateInvokeCode = "updateBaseTableSchemaRevision(" + filter.leadingCtxArg()
+		} else {
+			continue
+		}
+		leadingCode, invokeExpr := filter.customStepCode(schemaUpdateCustomCode, schemaUpdateInvokeCode+prop.metaKeySymbol()+", "+strconv.FormatInt(int64(idx), 10)+")")
+		if _, err = fp.WriteString("\t\tcase " + strconv.FormatInt(int64(idx+1), 10) + ":\n" +
+			leadingCode +
+			"\t\t\terr = " + invokeExpr + "\n"); nil != err {
+			return
+		}
+	}
+	return filter.writeSchemaDowngradeRoutineTail(fp)
+}
+
+func (filter *CodeGenerateFilter) generateBuilderSchemaDowngradeRoutine(fp *os.File, prop *tableProperty) (err error) {
+	paramAsArgs := strings.Join(parametersToArguments(prop.Entry.Parameters), ", ")
+	if err = filter.generateTransactionWrapperRoutine(fp, prop.downgradeRoutineSymbol(), "currentRev, targetRev int32, "+strings.Join(prop.Entry.Parameters, ", "), "currentRev, targetRev, "+paramAsArgs); nil != err {
+		return
+	}
+	if err = filter.writeSchemaDowngradeRoutineHead(fp, prop, filter.stepsRoutineSymbol(prop.downgradeRoutineSymbol()), ", "+strings.Join(prop.Entry.Parameters, ", ")); nil != err {
+		return
+	}
+	for idx := len(prop.RollbackEntries) - 1; idx >= 0; idx-- {
+		entry := prop.RollbackEntries[idx]
+		if nil == entry {
+			continue
+		}
+		var schemaUpdateCustomCode string
+		var schemaUpdateInvokeLeadingCode string
+		if rollbackEntrySymbol := prop.rollbackEntrySymbol(entry, int32(idx)); rollbackEntrySymbol != "" {
+			schemaUpdateInvokeLeadingCode = prop.execSchemaModificationSymbol() + "(" + filter.withCtxArg(sqlStatementsExpression(entry, rollbackEntrySymbol+"("+paramAsArgs+")")) + ", "
+		} else if entry.LanguageType == "go" {
+			if schemaUpdateCustomCode, err = customSchemaUpdateCode(entry); nil != err {
+				return
+			}
+			schemaUpdateInvokeLeadingCode = prop.updateSchemaRevisionSymbol() + "(" + filter.leadingCtxArg()
+		} else {
+			continue
+		}
+		leadingCode, invokeExpr := filter.customStepCode(schemaUpdateCustomCode, schemaUpdateInvokeLeadingCode+paramAsArgs+", "+strconv.FormatInt(int64(idx), 10)+")")
+		if _, err = fp.WriteString("\t\tcase " + strconv.FormatInt(int64(idx+1), 10) + ":\n" +
+			leadingCode +
+			"\t\t\terr = " + invokeExpr + "\n"); nil != err {
+			return
+		}
+	}
+	return filter.writeSchemaDowngradeRoutineTail(fp)
+}
+
+func (filter *CodeGenerateFilter) generateBuilderSchemaDowngradeWithRevisionRecordsRoutine(fp *os.File, prop *tableProperty) (err error) {
+	var params []string
+	for _, param := range parametersToArguments(prop.Entry.Parameters) {
+		params = append(params, "revRec."+param)
+	}
+	paramAsArgs := strings.Join(params, ", ")
+	if _, err = fp.WriteString("func (m *schemaManager) " + prop.downgradeWithRevisionRecordsRoutineSymbol() + "(" + filter.withCtxParam("revisionRecords []*"+filter.schemaRevisionRecordTypeSymbol(prop)+", targetRev int32") + ") (schemaChanged bool, err error) {\n" +
+		"\tfor _, revRec := range revisionRecords {\n" +
+		"\t\tif changed, err := m." + prop.downgradeRoutineSymbol() + "(" + filter.withCtxArg("revRec.currentRev, targetRev, "+paramAsArgs) + "); nil != err {\n" +
+		"\t\t\treturn schemaChanged, fmt.Errorf(\"downgrade " + prop.SymbolName + " failed (%#v): %#v\", revRec, err)\n" +
+		"\t\t} else if changed {\n" +
+		"\t\t\tschemaChanged = true\n" +
+		"\t\t}\n" +
+		"\t}\n" +
+		"\treturn schemaChanged, nil\n" +
+		"}\n\n"); nil != err {
+		return
+	}
+	return
+}
+
 func (filter *CodeGenerateFilter) generateBuilderExecSchemaModificationRoutine(fp *os.File, prop *tableProperty) (err error) {
-	_, _, revisionUpdateCodeTexts, err := prop.fetchRoutines()
+	_, revisionUpdateCodeTexts, err := filter.builderRoutines(prop)
 	if nil != err {
 		return
 	}
@@ -364,7 +577,7 @@ func (filter *CodeGenerateFilter) generateBuilderExecSchemaModificationRoutine(f
 		}
 		filter.increaseTODOCount()
 	}
-	if _, err = fp.WriteString("func (m *schemaManager) " + prop.updateSchemaRevisionSymbol() + "(" + strings.Join(prop.Entry.Parameters, ", ") + ", targetRev int32) (err error) {\n"); nil != err {
+	if _, err = fp.WriteString("func (m *schemaManager) " + prop.updateSchemaRevisionSymbol() + "(" + filter.withCtxParam(strings.Join(prop.Entry.Parameters, ", ")+", targetRev int32") + ") (err error) {\n"); nil != err {
 		return
 	}
 	for _, codeLine := range revisionUpdateCodeTexts {
@@ -376,16 +589,20 @@ func (filter *CodeGenerateFilter) generateBuilderExecSchemaModificationRoutine(f
 		"}\n\n"); nil != err {
 		return
 	}
-	if _, err = fp.WriteString("func (m *schemaManager) " + prop.execSchemaModificationSymbol() + "(sqlStmt string, " + strings.Join(prop.Entry.Parameters, ", ") + ", targetRev int32) (err error) {\n" +
-		"\tif _, err = m.conn.ExecContext(m.ctx, sqlStmt); nil != err {\n" +
-		"\t\treturn\n" +
+	if _, err = fp.WriteString("func (m *schemaManager) " + prop.execSchemaModificationSymbol() + "(" + filter.withCtxParam("sqlStmts []string, "+strings.Join(prop.Entry.Parameters, ", ")+", targetRev int32") + ") (err error) {\n" +
+		filter.stepTransactionOpenCode() +
+		"\tfor _, sqlStmt := range sqlStmts {\n" +
+		"\t\tif _, err = m.conn.ExecContext(" + filter.ctxExpr() + ", sqlStmt); nil != err {\n" +
+		"\t\t\treturn\n" +
+		"\t\t}\n" +
 		"\t}\n"); nil != err {
 		return
 	}
-	if _, err = fp.WriteString("\terr = m." + prop.updateSchemaRevisionSymbol() + "(" + strings.Join(parametersToArguments(prop.Entry.Parameters), ", ") + ", targetRev)\n"); nil != err {
+	if _, err = fp.WriteString("\terr = m." + prop.updateSchemaRevisionSymbol() + "(" + filter.withCtxArg(strings.Join(parametersToArguments(prop.Entry.Parameters), ", ")) + ", targetRev)\n"); nil != err {
 		return
 	}
 	if _, err = fp.WriteString("\treturn\n" +
+		filter.stepTransactionCloseCode() +
 		"}\n\n"); nil != err {
 		return
 	}
@@ -398,9 +615,9 @@ func (filter *CodeGenerateFilter) generateBuilderSchemaUpgradeWithRevisionRecord
 		params = append(params, "revRec."+param)
 	}
 	paramAsArgs := strings.Join(params, ", ")
-	if _, err = fp.WriteString("func (m *schemaManager) " + prop.upgradeWithRevisionRecordsRoutineSymbol() + "(revisionRecords []*" + prop.schemaRevisionRecordStructSymbol() + ") (schemaChanged bool, err error) {\n" +
+	if _, err = fp.WriteString("func (m *schemaManager) " + prop.upgradeWithRevisionRecordsRoutineSymbol() + "(" + filter.withCtxParam("revisionRecords []*"+filter.schemaRevisionRecordTypeSymbol(prop)) + ") (schemaChanged bool, err error) {\n" +
 		"\tfor _, revRec := range revisionRecords {\n" +
-		"\t\tif changed, err := m." + prop.upgradeRoutineSymbol() + "(revRec.currentRev, " + paramAsArgs + "); nil != err {\n" +
+		"\t\tif changed, err := m." + prop.upgradeRoutineSymbol() + "(" + filter.withCtxArg("revRec.currentRev, "+paramAsArgs) + "); nil != err {\n" +
 		"\t\t\treturn schemaChanged, fmt.Errorf(\"upgrade " + prop.SymbolName + " failed (%#v): %#v\", revRec, err)\n" +
 		"\t\t} else if changed {\n" +
 		"\t\t\tschemaChanged = true\n" +
@@ -415,12 +632,15 @@ func (filter *CodeGenerateFilter) generateBuilderSchemaUpgradeWithRevisionRecord
 
 func (filter *CodeGenerateFilter) generateBuilderSchemaUpgradeRoutine(fp *os.File, prop *tableProperty) (err error) {
 	paramAsArgs := strings.Join(parametersToArguments(prop.Entry.Parameters), ", ")
-	if _, err = fp.WriteString("func (m *schemaManager) " + prop.upgradeRoutineSymbol() + "(currentRev int32, " + strings.Join(prop.Entry.Parameters, ", ") + ") (schemaChanged bool, err error) {\n" +
+	if err = filter.generateTransactionWrapperRoutine(fp, prop.upgradeRoutineSymbol(), "currentRev int32, "+strings.Join(prop.Entry.Parameters, ", "), "currentRev, "+paramAsArgs); nil != err {
+		return
+	}
+	if _, err = fp.WriteString("func (m *schemaManager) " + filter.stepsRoutineSymbol(prop.upgradeRoutineSymbol()) + "(" + filter.withCtxParam("currentRev int32, "+strings.Join(prop.Entry.Parameters, ", ")) + ") (schemaChanged bool, err error) {\n" +
 		"\tswitch currentRev {\n" +
 		"\tcase " + prop.currentRevisionSymbol() + ":\n" +
 		"\t\treturn false, nil\n" +
 		"\tcase 0:\n" +
-		"\t\tif err = m." + prop.execSchemaModificationSymbol() + "(" + prop.sqlCreateSymbol() + "(" + paramAsArgs + ")" + ", " + paramAsArgs + ", " + prop.currentRevisionSymbol() + "); nil == err {\n" +
+		"\t\tif err = m." + prop.execSchemaModificationSymbol() + "(" + filter.withCtxArg(sqlStatementsExpression(prop.Entry, prop.sqlCreateSymbol()+"("+paramAsArgs+")")) + ", " + paramAsArgs + ", " + prop.currentRevisionSymbol() + "); nil == err {\n" +
 		"\t\t\treturn true, nil\n" +
 		"\t\t}\n"); nil != err {
 		return
@@ -433,20 +653,19 @@ func (filter *CodeGenerateFilter) generateBuilderSchemaUpgradeRoutine(fp *os.Fil
 		var schemaUpdateCustomCode string
 		var schemaUpdateInvokeLeadingCode string
 		if migrateEntrySymbol := prop.migrateEntrySymbol(entry, int32(sourceRev)); migrateEntrySymbol != "" {
-			schemaUpdateInvokeLeadingCode = prop.execSchemaModificationSymbol() + "(" + migrateEntrySymbol + "(" + paramAsArgs + "), "
+			schemaUpdateInvokeLeadingCode = prop.execSchemaModificationSymbol() + "(" + filter.withCtxArg(sqlStatementsExpression(entry, migrateEntrySymbol+"("+paramAsArgs+")")) + ", "
 		} else {
 			if entry.LanguageType == "go" {
-				var customCode []string
-				if customCode, err = entry.FilteredContent(); nil != err {
+				if schemaUpdateCustomCode, err = customSchemaUpdateCode(entry); nil != err {
 					return
 				}
-				schemaUpdateCustomCode = strings.TrimSpace(strings.Join(customCode, "\n")) + "\n"
 			}
-			schemaUpdateInvokeLeadingCode = prop.updateSchemaRevisionSymbol() + "("
+			schemaUpdateInvokeLeadingCode = prop.updateSchemaRevisionSymbol() + "(" + filter.leadingCtxArg()
 		}
+		leadingCode, invokeExpr := filter.customStepCode(schemaUpdateCustomCode, schemaUpdateInvokeLeadingCode+paramAsArgs+", "+strconv.FormatInt(int64(sourceRev+1), 10)+")")
 		if _, err = fp.WriteString("\tcase " + strconv.FormatInt(int64(sourceRev), 10) + ":\n" +
-			schemaUpdateCustomCode +
-			"\t\tif err = m." + schemaUpdateInvokeLeadingCode + paramAsArgs + ", " + strconv.FormatInt(int64(sourceRev+1), 10) + "); nil == err {\n" +
+			leadingCode +
+			"\t\tif err = " + invokeExpr + "; nil == err {\n" +
 			"\t\t\tschemaChanged = true\n"); nil != err {
 			return
 		}
@@ -475,7 +694,12 @@ func (filter *CodeGenerateFilter) generateBuilderSchemaUpgradeRoutine(fp *os.Fil
 }
 
 func (filter *CodeGenerateFilter) generateBuilderSchemaRevisionStructure(fp *os.File, prop *tableProperty) (err error) {
-	if _, err = fp.WriteString("type " + prop.schemaRevisionRecordStructSymbol() + " struct {\n" +
+	if filter.ContextPassing {
+		if _, err = fp.WriteString("type " + prop.schemaRevisionRecordStructSymbol() + " = " + filter.schemaRevisionRecordTypeSymbol(prop) + "\n\n"); nil != err {
+			return
+		}
+	}
+	if _, err = fp.WriteString("type " + filter.schemaRevisionRecordTypeSymbol(prop) + " struct {\n" +
 		"\tcurrentRev int32\n"); nil != err {
 		return
 	}
@@ -487,14 +711,17 @@ func (filter *CodeGenerateFilter) generateBuilderSchemaRevisionStructure(fp *os.
 	if _, err = fp.WriteString("}\n\n"); nil != err {
 		return
 	}
+	if filter.ContextPassing {
+		err = filter.generateSchemaRevisionRecordAccessors(fp, prop)
+	}
 	return
 }
 
 func (filter *CodeGenerateFilter) generateBuilderFetchSchemaRevisionRoutine(fp *os.File, prop *tableProperty) (err error) {
-	if _, err = fp.WriteString("func (m *schemaManager) " + prop.fetchSchemaRevisionRecordsSymbol() + "() (revisionRecords []*" + prop.schemaRevisionRecordStructSymbol() + ", err error) {\n"); nil != err {
+	if _, err = fp.WriteString("func (m *schemaManager) " + prop.fetchSchemaRevisionRecordsSymbol() + "(" + filter.withCtxParam("") + ") (revisionRecords []*" + prop.schemaRevisionRecordStructSymbol() + ", err error) {\n"); nil != err {
 		return
 	}
-	_, revisionFetchCodeTexts, _, err := prop.fetchRoutines()
+	revisionFetchCodeTexts, _, err := filter.builderRoutines(prop)
 	if nil != err {
 		return
 	}
@@ -520,12 +747,25 @@ func (filter *CodeGenerateFilter) generateSchemaUpgradeCodes(fp *os.File) (err e
 	for _, prop := range filter.TableProperties {
 		switch prop.Entry.TranslationMode {
 		case literalcodegen.TranslateAsConst:
-			err = filter.generateBaseSchemaUpgradeRoutine(fp, prop)
+			if err = filter.generateBaseSchemaUpgradeRoutine(fp, prop); nil != err {
+				return
+			}
+			if prop.hasRollbackEntries() {
+				err = filter.generateBaseSchemaDowngradeRoutine(fp, prop)
+			}
 		case literalcodegen.TranslateAsBuilder:
 			if err = filter.generateBuilderSchemaUpgradeRoutine(fp, prop); nil != err {
 				return
 			}
-			err = filter.generateBuilderSchemaUpgradeWithRevisionRecordsRoutine(fp, prop)
+			if err = filter.generateBuilderSchemaUpgradeWithRevisionRecordsRoutine(fp, prop); nil != err {
+				return
+			}
+			if prop.hasRollbackEntries() {
+				if err = filter.generateBuilderSchemaDowngradeRoutine(fp, prop); nil != err {
+					return
+				}
+				err = filter.generateBuilderSchemaDowngradeWithRevisionRecordsRoutine(fp, prop)
+			}
 		default:
 			if _, err = fp.WriteString("// upgrade routine for symbol not generated: " + prop.SymbolName + "\n"); nil != err {
 				return
@@ -558,6 +798,9 @@ func (filter *CodeGenerateFilter) GenerateExternalCode(fp *os.File, entries []*l
 	if err = filter.generateSchemaUpgradeCodes(fp); nil != err {
 		return
 	}
+	if err = filter.generateSchemaManagerInterface(fp); nil != err {
+		return
+	}
 	if _, err = fp.WriteString("\n" + fmt.Sprintf("// ** Generated code for %d table entries\n", len(filter.TableProperties))); nil != err {
 		return
 	}
@@ -0,0 +1,120 @@
+package sqlschema
+
+import (
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/yinyin/go-literal-code-gen/literalcodegen"
+)
+
+const schemaTestMarkdown = "# Heading Code\n\n" +
+	"```go\npackage schema\n\nimport (\n\t\"context\"\n\t\"database/sql\"\n\t\"fmt\"\n)\n```\n\n" +
+	"# MetaStore (meta_store) r.1\n\n" +
+	"* `const`: `-`\n\n" +
+	"```sql\nCREATE TABLE meta_store (meta_key VARCHAR(64) PRIMARY KEY, meta_value INTEGER)\n```\n\n" +
+	"## Routines\n\n" +
+	"### fetch revision\n\n" +
+	"```go\n\tif err = m.conn.QueryRowContext(ctx, \"SELECT meta_value FROM meta_store WHERE meta_key = $1\", ${SCHEMA_REV_KEY}).Scan(&${SCHEMA_REV_VAR}); nil != err && err != sql.ErrNoRows {\n\t\treturn\n\t}\n```\n\n" +
+	"### update revision\n\n" +
+	"```go\n\t_, err = m.conn.ExecContext(ctx, \"UPDATE meta_store SET meta_value = $1 WHERE meta_key = $2\", rev, key)\n```\n\n" +
+	"# User (user) r.3\n\n" +
+	"* `const`: `-`\n\n" +
+	"```sql\nCREATE TABLE user (id INTEGER PRIMARY KEY, name TEXT, email TEXT)\n```\n\n" +
+	"## Migrations\n\n" +
+	"### To r.2\n\n" +
+	"```sql\nALTER TABLE user ADD COLUMN name TEXT\n```\n\n" +
+	"### From r.2\n\n" +
+	"```sql\nALTER TABLE user DROP COLUMN name\n```\n\n" +
+	"### To r.3\n\n" +
+	"```go\n\tif _, err = m.conn.ExecContext(ctx, \"ALTER TABLE user ADD COLUMN email TEXT\"); nil != err {\n\t\treturn\n\t}\n```\n\n" +
+	"### From r.3\n\n" +
+	"```go\n\tif _, err = m.conn.ExecContext(ctx, \"ALTER TABLE user DROP COLUMN email\"); nil != err {\n\t\treturn\n\t}\n```\n"
+
+// generateSchemaCode generate code from given Markdown text with a filter
+// of given options and return the generated code.
+func generateSchemaCode(t *testing.T, text string, transactionScope string, contextPassing bool) string {
+	tempDir, err := ioutil.TempDir("", "sqlschema-test-")
+	if nil != err {
+		t.Fatalf("cannot create temporary folder: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	inputPath := tempDir + "/schema.md"
+	outputPath := tempDir + "/schema.go"
+	if err = ioutil.WriteFile(inputPath, []byte(text), 0644); nil != err {
+		t.Fatalf("cannot write Markdown input: %v", err)
+	}
+	code, err := literalcodegen.ParseMarkdown(inputPath)
+	if nil != err {
+		t.Fatalf("cannot parse Markdown input: %v", err)
+	}
+	filter := NewCodeGenerateFilter()
+	filter.TransactionScope = transactionScope
+	filter.ContextPassing = contextPassing
+	if err = literalcodegen.GenerateGoCodeFile(outputPath, code, false, filter); nil != err {
+		t.Fatalf("cannot generate code: %v", err)
+	}
+	buf, err := ioutil.ReadFile(outputPath)
+	if nil != err {
+		t.Fatalf("cannot read generated code: %v", err)
+	}
+	if _, err = parser.ParseFile(token.NewFileSet(), outputPath, buf, 0); nil != err {
+		t.Errorf("generated code cannot be parsed: %v", err)
+	}
+	return string(buf)
+}
+
+func TestGenerateBaseSchemaGoMigration(t *testing.T) {
+	for _, transactionScope := range []string{TransactionScopeNone, TransactionScopeStep, TransactionScopeUpgrade} {
+		result := generateSchemaCode(t, schemaTestMarkdown, transactionScope, true)
+		for _, expect := range []string{
+			"execBaseSchemaModification(ctx, []string{sqlMigrateUserToRev2}, metaKeyUserSchemaRev, 2)",
+			"\"ALTER TABLE user ADD COLUMN email TEXT\"",
+			"updateBaseTableSchemaRevision(ctx, metaKeyUserSchemaRev, 3)",
+			"execBaseSchemaModification(ctx, []string{sqlRollbackUserFromRev2}, metaKeyUserSchemaRev, 1)",
+			"\"ALTER TABLE user DROP COLUMN email\"",
+			"updateBaseTableSchemaRevision(ctx, metaKeyUserSchemaRev, 2)",
+		} {
+			if !strings.Contains(result, expect) {
+				t.Errorf("tx=%q: expecting %q in generated code", transactionScope, expect)
+			}
+		}
+		if strings.Contains(result, "[]string{}") {
+			t.Errorf("tx=%q: unexpected empty statement list in generated code", transactionScope)
+		}
+	}
+}
+
+const builderSchemaTestMarkdown = schemaTestMarkdown + "\n" +
+	"# Log (log) r.1\n\n" +
+	"* `builder`: `-`, `shard int`, `region string`\n\n" +
+	"```sql\nCREATE TABLE log_SHARD (id INTEGER PRIMARY KEY, msg TEXT)\n```\n\n" +
+	"## Routines\n\n" +
+	"### fetch revision\n\n" +
+	"```go\n\trevisionRecords = append(revisionRecords, &schemaRevisionOfLog{currentRev: 0, shard: 0})\n```\n\n" +
+	"### update revision\n\n" +
+	"```go\n\t_, err = m.conn.ExecContext(ctx, \"UPDATE meta_store SET meta_value = $1 WHERE meta_key = $2\", targetRev, fmt.Sprintf(\"log_%d_%s\", shard, region))\n```\n"
+
+func TestGenerateSchemaRevisionRecordAccessors(t *testing.T) {
+	expects := []string{
+		"func NewSchemaRevisionOfLog(currentRev int32, shard int, region string) *SchemaRevisionOfLog {",
+		"func (r *SchemaRevisionOfLog) CurrentRevision() int32 {",
+		"func (r *SchemaRevisionOfLog) Shard() int {",
+		"func (r *SchemaRevisionOfLog) Region() string {",
+	}
+	result := generateSchemaCode(t, builderSchemaTestMarkdown, TransactionScopeNone, true)
+	for _, expect := range expects {
+		if !strings.Contains(result, expect) {
+			t.Errorf("expecting %q in generated code", expect)
+		}
+	}
+	result = generateSchemaCode(t, strings.Replace(builderSchemaTestMarkdown, "ctx, ", "", -1), TransactionScopeNone, false)
+	for _, expect := range expects {
+		if strings.Contains(result, expect) {
+			t.Errorf("unexpected %q in generated code without context passing", expect)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package sqlschema
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/yinyin/go-literal-code-gen/literalcodegen"
+)
+
+// revisionRoutineTemplate is the built-in SQL of revision routines for one
+// dialect. Revisions are kept in meta table with `meta_key` and `meta_value`
+// columns. Table name of meta table is given with `%s` verb.
+type revisionRoutineTemplate struct {
+	// TableExistedQuery count tables with name given as the only parameter
+	TableExistedQuery string
+
+	// FetchRevisionQuery select `meta_value` of key given as the only parameter
+	FetchRevisionQuery string
+
+	// FetchRevisionsQuery select `meta_key` and `meta_value` of keys matching
+	// LIKE pattern given as the only parameter
+	FetchRevisionsQuery string
+
+	// UpdateRevisionStatement insert or update `meta_value` (second
+	// parameter) of key (first parameter)
+	UpdateRevisionStatement string
+}
+
+var revisionRoutineTemplates = map[string]*revisionRoutineTemplate{
+	literalcodegen.SQLDialectPostgres: {
+		TableExistedQuery:       "SELECT COUNT(*) FROM information_schema.tables WHERE (table_schema = current_schema()) AND (table_name = $1)",
+		FetchRevisionQuery:      "SELECT meta_value FROM %s WHERE meta_key = $1",
+		FetchRevisionsQuery:     "SELECT meta_key, meta_value FROM %s WHERE meta_key LIKE $1",
+		UpdateRevisionStatement: "INSERT INTO %s (meta_key, meta_value) VALUES ($1, $2) ON CONFLICT (meta_key) DO UPDATE SET meta_value = EXCLUDED.meta_value",
+	},
+	literalcodegen.SQLDialectMySQL: {
+		TableExistedQuery:       "SELECT COUNT(*) FROM information_schema.tables WHERE (table_schema = DATABASE()) AND (table_name = ?)",
+		FetchRevisionQuery:      "SELECT meta_value FROM %s WHERE meta_key = ?",
+		FetchRevisionsQuery:     "SELECT meta_key, meta_value FROM %s WHERE meta_key LIKE ?",
+		UpdateRevisionStatement: "INSERT INTO %s (meta_key, meta_value) VALUES (?, ?) ON DUPLICATE KEY UPDATE meta_value = VALUES(meta_value)",
+	},
+	literalcodegen.SQLDialectSQLite: {
+		TableExistedQuery:       "SELECT COUNT(*) FROM sqlite_master WHERE (type = 'table') AND (name = ?)",
+		FetchRevisionQuery:      "SELECT meta_value FROM %s WHERE meta_key = ?",
+		FetchRevisionsQuery:     "SELECT meta_key, meta_value FROM %s WHERE meta_key LIKE ?",
+		UpdateRevisionStatement: "INSERT OR REPLACE INTO %s (meta_key, meta_value) VALUES (?, ?)",
+	},
+}
+
+const metaTableExistedRoutineSymbol = "isSchemaMetaTableExisted"
+
+func (tpl *revisionRoutineTemplate) tableExistedRoutineCode(filter *CodeGenerateFilter, metaTableName string) string {
+	return "func (m *schemaManager) " + metaTableExistedRoutineSymbol + "(" + filter.withCtxParam("") + ") (existed bool, err error) {\n" +
+		"\tvar count int\n" +
+		"\tif err = m.conn.QueryRowContext(" + filter.ctxExpr() + ", " + strconv.Quote(tpl.TableExistedQuery) + ", " + strconv.Quote(metaTableName) + ").Scan(&count); nil != err {\n" +
+		"\t\treturn\n" +
+		"\t}\n" +
+		"\treturn count > 0, nil\n" +
+		"}\n\n"
+}
+
+// baseRoutines return code lines of revision routines for constant tables.
+// Code of fetch revision is a template of SCHEMA_REV_KEY and SCHEMA_REV_VAR.
+func (tpl *revisionRoutineTemplate) baseRoutines(filter *CodeGenerateFilter, metaTableName string) (revisionFetchPrepareCodeTexts, revisionFetchCodeTexts, revisionUpdateCodeTexts []string) {
+	revisionFetchPrepareCodeTexts = []string{
+		"metaTableExisted, err := m." + metaTableExistedRoutineSymbol + "(" + filter.withCtxArg("") + ")",
+		"if nil != err {",
+		"return nil, err",
+		"}",
+	}
+	revisionFetchCodeTexts = []string{
+		"if metaTableExisted {",
+		"if err = m.conn.QueryRowContext(" + filter.ctxExpr() + ", " + strconv.Quote(fmt.Sprintf(tpl.FetchRevisionQuery, metaTableName)) + ", ${SCHEMA_REV_KEY}).Scan(&${SCHEMA_REV_VAR}); (nil != err) && (sql.ErrNoRows != err) {",
+		"return nil, err",
+		"}",
+		"}",
+	}
+	revisionUpdateCodeTexts = []string{
+		"_, err = m.conn.ExecContext(" + filter.ctxExpr() + ", " + strconv.Quote(fmt.Sprintf(tpl.UpdateRevisionStatement, metaTableName)) + ", key, rev)",
+	}
+	return
+}
+
+// builderParameter is a builder parameter of table which can be encoded into
+// meta key.
+type builderParameter struct {
+	Name string
+	Type string
+}
+
+func (param *builderParameter) isSigned() bool {
+	switch param.Type {
+	case "int", "int8", "int16", "int32", "int64":
+		return true
+	}
+	return false
+}
+
+func (param *builderParameter) isUnsigned() bool {
+	switch param.Type {
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		return true
+	}
+	return false
+}
+
+func (param *builderParameter) bitSize() string {
+	if s := strings.TrimLeft(param.Type, "uint"); s != "" {
+		return s
+	}
+	return "0"
+}
+
+func (param *builderParameter) formatCode() string {
+	switch {
+	case param.isSigned():
+		return "strconv.FormatInt(int64(" + param.Name + "), 10)"
+	case param.isUnsigned():
+		return "strconv.FormatUint(uint64(" + param.Name + "), 10)"
+	}
+	return param.Name
+}
+
+func parseBuilderParameters(params []string) (result []*builderParameter, err error) {
+	for _, param := range params {
+		aux := strings.Fields(param)
+		if len(aux) != 2 {
+			return nil, fmt.Errorf("unsupported parameter definition: %q", param)
+		}
+		p := &builderParameter{
+			Name: aux[0],
+			Type: aux[1],
+		}
+		if !p.isSigned() && !p.isUnsigned() && (p.Type != "string") {
+			return nil, fmt.Errorf("unsupported parameter type: %q", param)
+		}
+		result = append(result, p)
+	}
+	return
+}
+
+// metaKeyCode return expression of meta key for parameters of builder table.
+func (prop *tableProperty) metaKeyCode(params []*builderParameter) string {
+	parts := []string{prop.metaKeySymbol()}
+	for _, param := range params {
+		parts = append(parts, "\":\"", param.formatCode())
+	}
+	return strings.Join(parts, " + ")
+}
+
+// builderRoutines return code lines of revision routines for builder table.
+// Parameters of table are encoded into meta key in the form of
+// `META_KEY:PARAM1:PARAM2`.
+func (tpl *revisionRoutineTemplate) builderRoutines(filter *CodeGenerateFilter, metaTableName string, prop *tableProperty) (revisionFetchCodeTexts, revisionUpdateCodeTexts []string, err error) {
+	params, err := parseBuilderParameters(prop.Entry.Parameters)
+	if nil != err {
+		return
+	}
+	keyPrefixCode := prop.metaKeySymbol() + " + \":\""
+	revisionFetchCodeTexts = []string{
+		"metaTableExisted, err := m." + metaTableExistedRoutineSymbol + "(" + filter.withCtxArg("") + ")",
+		"if (nil != err) || !metaTableExisted {",
+		"return",
+		"}",
+		"rows, err := m.conn.QueryContext(" + filter.ctxExpr() + ", " + strconv.Quote(fmt.Sprintf(tpl.FetchRevisionsQuery, metaTableName)) + ", " + keyPrefixCode + " + \"%\")",
+		"if nil != err {",
+		"return",
+		"}",
+		"defer rows.Close()",
+		"for rows.Next() {",
+		"var metaKey string",
+		"revRec := &" + prop.schemaRevisionRecordStructSymbol() + "{}",
+		"if err = rows.Scan(&metaKey, &revRec.currentRev); nil != err {",
+		"return nil, err",
+		"}",
+		"if !strings.HasPrefix(metaKey, " + keyPrefixCode + ") {",
+		"continue",
+		"}",
+		"args := strings.SplitN(strings.TrimPrefix(metaKey, " + keyPrefixCode + "), \":\", " + strconv.Itoa(len(params)) + ")",
+		"if len(args) != " + strconv.Itoa(len(params)) + " {",
+		"continue",
+		"}",
+	}
+	for idx, param := range params {
+		argCode := "args[" + strconv.Itoa(idx) + "]"
+		var parseCode string
+		switch {
+		case param.isSigned():
+			parseCode = "strconv.ParseInt(" + argCode + ", 10, " + param.bitSize() + ")"
+		case param.isUnsigned():
+			parseCode = "strconv.ParseUint(" + argCode + ", 10, " + param.bitSize() + ")"
+		default:
+			revisionFetchCodeTexts = append(revisionFetchCodeTexts, "revRec."+param.Name+" = "+argCode)
+			continue
+		}
+		revisionFetchCodeTexts = append(revisionFetchCodeTexts,
+			"if v, err := "+parseCode+"; nil == err {",
+			"revRec."+param.Name+" = "+param.Type+"(v)",
+			"} else {",
+			"return nil, fmt.Errorf(\"invalid "+param.Name+" in schema revision key %q: %v\", metaKey, err)",
+			"}")
+	}
+	revisionFetchCodeTexts = append(revisionFetchCodeTexts,
+		"revisionRecords = append(revisionRecords, revRec)",
+		"}",
+		"err = rows.Err()")
+	revisionUpdateCodeTexts = []string{
+		"_, err = m.conn.ExecContext(" + filter.ctxExpr() + ", " + strconv.Quote(fmt.Sprintf(tpl.UpdateRevisionStatement, metaTableName)) + ", " + prop.metaKeyCode(params) + ", targetRev)",
+	}
+	return
+}
+
+// revisionRoutineTemplate return built-in routine template of dialect of
+// meta table. Nil is returned if dialect is not given or not supported.
+func (filter *CodeGenerateFilter) revisionRoutineTemplate() *revisionRoutineTemplate {
+	if nil == filter.MetaTableEntry {
+		return nil
+	}
+	dialect := filter.MetaTableEntry.SQLDialect
+	if dialect == "" {
+		return nil
+	}
+	tpl, ok := revisionRoutineTemplates[dialect]
+	if !ok {
+		log.Printf("WARN: built-in revision routines are not available for SQL dialect: %s", dialect)
+		return nil
+	}
+	return tpl
+}
+
+func (filter *CodeGenerateFilter) metaTableName() string {
+	return filter.TableProperties[0].MetaName
+}
+
+// baseRoutines return revision routines for constant tables. Routines which
+// are not given in `Routines` section of meta table are filled with built-in
+// routines of dialect of meta table.
+func (filter *CodeGenerateFilter) baseRoutines() (revisionFetchPrepareCodeTexts, revisionFetchCodeTexts, revisionUpdateCodeTexts []string, err error) {
+	metaTableProp := filter.TableProperties[0]
+	if revisionFetchPrepareCodeTexts, revisionFetchCodeTexts, revisionUpdateCodeTexts, err = metaTableProp.fetchRoutines(); nil != err {
+		return
+	}
+	tpl := filter.revisionRoutineTemplate()
+	if (nil == tpl) || ((len(revisionFetchCodeTexts) > 0) && (len(revisionUpdateCodeTexts) > 0)) {
+		return
+	}
+	tplFetchPrepare, tplFetch, tplUpdate := tpl.baseRoutines(filter, filter.metaTableName())
+	if len(revisionFetchCodeTexts) == 0 {
+		if len(revisionFetchPrepareCodeTexts) == 0 {
+			revisionFetchPrepareCodeTexts = tplFetchPrepare
+		}
+		revisionFetchCodeTexts = tplFetch
+		filter.useMetaTableExistedRoutine = true
+	}
+	if len(revisionUpdateCodeTexts) == 0 {
+		revisionUpdateCodeTexts = tplUpdate
+	}
+	return
+}
+
+// builderRoutines return revision fetch and update routines of builder
+// table. Routines not given in `Routines` section of table are filled with
+// built-in routines of dialect of meta table if possible.
+func (filter *CodeGenerateFilter) builderRoutines(prop *tableProperty) (revisionFetchCodeTexts, revisionUpdateCodeTexts []string, err error) {
+	if _, revisionFetchCodeTexts, revisionUpdateCodeTexts, err = prop.fetchRoutines(); nil != err {
+		return
+	}
+	tpl := filter.revisionRoutineTemplate()
+	if (nil == tpl) || ((len(revisionFetchCodeTexts) > 0) && (len(revisionUpdateCodeTexts) > 0)) {
+		return
+	}
+	tplFetch, tplUpdate, err := tpl.builderRoutines(filter, filter.metaTableName(), prop)
+	if nil != err {
+		log.Printf("WARN: built-in revision routines are not available for table %s: %v", prop.SymbolName, err)
+		return revisionFetchCodeTexts, revisionUpdateCodeTexts, nil
+	}
+	if len(revisionFetchCodeTexts) == 0 {
+		revisionFetchCodeTexts = tplFetch
+		filter.useMetaTableExistedRoutine = true
+	}
+	if len(revisionUpdateCodeTexts) == 0 {
+		revisionUpdateCodeTexts = tplUpdate
+	}
+	return
+}
@@ -13,6 +13,7 @@ type tableProperty struct {
 	Revision         int32
 	Entry            *literalcodegen.LiteralEntry
 	MigrationEntries []*literalcodegen.LiteralEntry
+	RollbackEntries  []*literalcodegen.LiteralEntry
 }
 
 func newTablePropertyFromTitle1(entry *literalcodegen.LiteralEntry) (prop *tableProperty) {
@@ -37,6 +38,7 @@ func newTablePropertyFromTitle1(entry *literalcodegen.LiteralEntry) (prop *table
 		Revision:         int32(revValue),
 		Entry:            entry,
 		MigrationEntries: make([]*literalcodegen.LiteralEntry, revValue),
+		RollbackEntries:  make([]*literalcodegen.LiteralEntry, revValue),
 	}
 	prop.initMigrationEntries()
 	return prop
@@ -79,20 +81,30 @@ func (prop *tableProperty) feedMigrationEntries(entries []*literalcodegen.Litera
 		if entry.TitleText == "" {
 			continue
 		}
-		m := migrateRevTitleTrap.FindStringSubmatchIndex(entry.TitleText)
-		if nil == m {
-			continue
-		}
-		targetRevText := entry.TitleText[m[2]:m[3]]
-		if targetRevValue, err := strconv.ParseInt(targetRevText, 10, 31); nil != err {
-			log.Printf("WARN: failed on parsing revision value (%v): %v, %v: %v", prop.SymbolName, targetRevText, entry.TitleText, err)
-			continue
-		} else if (targetRevValue <= 1) || (int(targetRevValue) > len(prop.MigrationEntries)) {
-			log.Printf("WARN: revision out of boundary (%v): %v (max-rev=%v)", prop.SymbolName, targetRevValue, prop.Revision)
-			continue
-		} else {
-			sourceRevValue := int(targetRevValue) - 1
-			prop.MigrationEntries[sourceRevValue] = entry
+		if m := migrateRevTitleTrap.FindStringSubmatchIndex(entry.TitleText); nil != m {
+			targetRevText := entry.TitleText[m[2]:m[3]]
+			if targetRevValue, err := strconv.ParseInt(targetRevText, 10, 31); nil != err {
+				log.Printf("WARN: failed on parsing revision value (%v): %v, %v: %v", prop.SymbolName, targetRevText, entry.TitleText, err)
+				continue
+			} else if (targetRevValue <= 1) || (int(targetRevValue) > len(prop.MigrationEntries)) {
+				log.Printf("WARN: revision out of boundary (%v): %v (max-rev=%v)", prop.SymbolName, targetRevValue, prop.Revision)
+				continue
+			} else {
+				sourceRevValue := int(targetRevValue) - 1
+				prop.MigrationEntries[sourceRevValue] = entry
+			}
+		} else if m := rollbackRevTitleTrap.FindStringSubmatchIndex(entry.TitleText); nil != m {
+			sourceRevText := entry.TitleText[m[2]:m[3]]
+			if sourceRevValue, err := strconv.ParseInt(sourceRevText, 10, 31); nil != err {
+				log.Printf("WARN: failed on parsing rollback revision value (%v): %v, %v: %v", prop.SymbolName, sourceRevText, entry.TitleText, err)
+				continue
+			} else if (sourceRevValue < 1) || (int(sourceRevValue) > len(prop.RollbackEntries)) {
+				log.Printf("WARN: rollback revision out of boundary (%v): %v (max-rev=%v)", prop.SymbolName, sourceRevValue, prop.Revision)
+				continue
+			} else {
+				targetRevValue := int(sourceRevValue) - 1
+				prop.RollbackEntries[targetRevValue] = entry
+			}
 		}
 	}
 }
@@ -107,6 +119,23 @@ func (prop *tableProperty) warnEmptyMigrationEntries() {
 	}
 }
 
+func (prop *tableProperty) warnMissingRollbackEntries() {
+	for idx, forwardEntry := range prop.MigrationEntries {
+		if (nil != forwardEntry) && (nil == prop.RollbackEntries[idx]) {
+			log.Printf("WARN: migration to revision %d without rollback: %v", idx+1, prop.SymbolName)
+		}
+	}
+}
+
+func (prop *tableProperty) hasRollbackEntries() bool {
+	for _, entry := range prop.RollbackEntries {
+		if nil != entry {
+			return true
+		}
+	}
+	return false
+}
+
 func (prop *tableProperty) initMigrationEntries() {
 	for _, entry := range prop.Entry.ChildEntries {
 		if entry.TitleText != "Migrations" {
@@ -116,6 +145,7 @@ func (prop *tableProperty) initMigrationEntries() {
 		prop.feedMigrationEntries(entry.ChildEntries)
 	}
 	prop.warnEmptyMigrationEntries()
+	prop.warnMissingRollbackEntries()
 }
 
 func (prop *tableProperty) updateMigrationEntryParameters(entry *literalcodegen.LiteralEntry) {
@@ -135,6 +165,13 @@ func (prop *tableProperty) setupEntriesPrototypes() {
 		prop.updateMigrationEntryParameters(entry)
 		entry.Name = prop.migrateEntrySymbol(entry, int32(idx))
 	}
+	for idx, entry := range prop.RollbackEntries {
+		if nil == entry {
+			continue
+		}
+		prop.updateMigrationEntryParameters(entry)
+		entry.Name = prop.rollbackEntrySymbol(entry, int32(idx))
+	}
 }
 
 func (prop *tableProperty) metaKeySymbol() string {
@@ -165,6 +202,22 @@ func (prop *tableProperty) migrateEntrySymbol(entry *literalcodegen.LiteralEntry
 	return symbolPrefix + prop.SymbolName + "ToRev" + strconv.FormatInt(int64(sourceRev+1), 10)
 }
 
+func (prop *tableProperty) rollbackEntrySymbol(entry *literalcodegen.LiteralEntry, targetRev int32) string {
+	if entry.LanguageType != "sql" {
+		return ""
+	}
+	if content, err := entry.FilteredContent(); (nil == err) && (len(content) == 0) {
+		return ""
+	}
+	var symbolPrefix string
+	if entry.TranslationMode == literalcodegen.TranslateAsBuilder {
+		symbolPrefix = "makeSQLRollback"
+	} else {
+		symbolPrefix = "sqlRollback"
+	}
+	return symbolPrefix + prop.SymbolName + "FromRev" + strconv.FormatInt(int64(targetRev+1), 10)
+}
+
 func (prop *tableProperty) upgradeRoutineSymbol() string {
 	if prop.Entry.TranslationMode == literalcodegen.TranslateAsConst {
 		return "UpgradeSchema" + prop.SymbolName
@@ -179,6 +232,20 @@ func (prop *tableProperty) upgradeWithRevisionRecordsRoutineSymbol() string {
 	return "upgradeSchema" + prop.SymbolName + "WithRevisions"
 }
 
+func (prop *tableProperty) downgradeRoutineSymbol() string {
+	if prop.Entry.TranslationMode == literalcodegen.TranslateAsConst {
+		return "DowngradeSchema" + prop.SymbolName
+	}
+	return "downgradeSchema" + prop.SymbolName
+}
+
+func (prop *tableProperty) downgradeWithRevisionRecordsRoutineSymbol() string {
+	if prop.Entry.TranslationMode == literalcodegen.TranslateAsBuilder {
+		return "DowngradeSchemaOf" + prop.SymbolName
+	}
+	return "downgradeSchema" + prop.SymbolName + "WithRevisions"
+}
+
 func (prop *tableProperty) updateSchemaRevisionSymbol() string {
 	return "update" + prop.SymbolName + "SchemaRevision"
 }
@@ -0,0 +1,145 @@
+package sqlschema
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Scopes of transaction for schema modifications.
+const (
+	// TransactionScopeNone run schema modifications without transaction
+	TransactionScopeNone = ""
+
+	// TransactionScopeStep run each migration step and its revision update
+	// in one transaction
+	TransactionScopeStep = "step"
+
+	// TransactionScopeUpgrade run all steps of an upgrade (or downgrade) in
+	// one transaction
+	TransactionScopeUpgrade = "upgrade"
+)
+
+// CheckTransactionScope checks if given transaction scope is supported.
+func CheckTransactionScope(scope string) (err error) {
+	switch scope {
+	case TransactionScopeNone, TransactionScopeStep, TransactionScopeUpgrade:
+		return nil
+	}
+	return fmt.Errorf("unknown transaction scope: %q (expecting %q or %q)", scope, TransactionScopeStep, TransactionScopeUpgrade)
+}
+
+func (filter *CodeGenerateFilter) transactional() bool {
+	return filter.TransactionScope != TransactionScopeNone
+}
+
+func (filter *CodeGenerateFilter) generateSchemaConnInterface(fp *os.File) (err error) {
+	_, err = fp.WriteString("// schemaConn is implemented by both *sql.DB and *sql.Tx.\n" +
+		"type schemaConn interface {\n" +
+		"\tExec(query string, args ...interface{}) (sql.Result, error)\n" +
+		"\tExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)\n" +
+		"\tQuery(query string, args ...interface{}) (*sql.Rows, error)\n" +
+		"\tQueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)\n" +
+		"\tQueryRow(query string, args ...interface{}) *sql.Row\n" +
+		"\tQueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row\n" +
+		"}\n\n")
+	return
+}
+
+func (filter *CodeGenerateFilter) generateWithSchemaTxRoutine(fp *os.File) (err error) {
+	// *sql.Conn can also start transaction when context is passed in.
+	beginnerType := "*sql.DB"
+	ctxFallbackCode := "\tctx := m.ctx\n" +
+		"\tif nil == ctx {\n" +
+		"\t\tctx = context.Background()\n" +
+		"\t}\n"
+	if filter.ContextPassing {
+		beginnerType = "interface {\n" +
+			"\t\tBeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)\n" +
+			"\t}"
+		ctxFallbackCode = ""
+	}
+	_, err = fp.WriteString("// withSchemaTx run fn with schema manager bound to a transaction. The\n" +
+		"// transaction is committed if fn succeeds. Transaction of m is reused if m\n" +
+		"// is already bound to one.\n" +
+		"func (m *schemaManager) withSchemaTx(" + filter.withCtxParam("fn func(txm *schemaManager) error") + ") (err error) {\n" +
+		"\tdb, ok := m.conn.(" + beginnerType + ")\n" +
+		"\tif !ok {\n" +
+		"\t\treturn fn(m)\n" +
+		"\t}\n" +
+		ctxFallbackCode +
+		"\ttx, err := db.BeginTx(ctx, nil)\n" +
+		"\tif nil != err {\n" +
+		"\t\treturn\n" +
+		"\t}\n" +
+		"\ttxm := *m\n" +
+		"\ttxm.conn = tx\n" +
+		"\tif err = fn(&txm); nil != err {\n" +
+		"\t\ttx.Rollback()\n" +
+		"\t\treturn\n" +
+		"\t}\n" +
+		"\treturn tx.Commit()\n" +
+		"}\n\n")
+	return
+}
+
+// stepTransactionOpenCode return code which starts wrapping body of schema
+// modification routine with transaction. The wrapped body refers to schema
+// manager bound to the transaction as `m`.
+func (filter *CodeGenerateFilter) stepTransactionOpenCode() string {
+	if !filter.transactional() {
+		return ""
+	}
+	return "\treturn m.withSchemaTx(" + filter.withCtxArg("func(m *schemaManager) (err error) {") + "\n"
+}
+
+// stepTransactionCloseCode return code which ends wrapping started with
+// stepTransactionOpenCode.
+func (filter *CodeGenerateFilter) stepTransactionCloseCode() string {
+	if !filter.transactional() {
+		return ""
+	}
+	return "\t})\n"
+}
+
+// customStepCode return leading code and invoke expression which run custom
+// Go code of a migration (or rollback) step and then the revision update of
+// given invoke code (without the leading `m.`). Both are run in one
+// transaction when transaction scope is step.
+func (filter *CodeGenerateFilter) customStepCode(customCode, updateInvokeCode string) (leadingCode, invokeExpr string) {
+	if (customCode == "") || (filter.TransactionScope != TransactionScopeStep) {
+		return customCode, "m." + updateInvokeCode
+	}
+	return "", "m.withSchemaTx(" + filter.withCtxArg("func(m *schemaManager) (err error) {") + "\n" +
+		customCode +
+		"\treturn m." + updateInvokeCode + "\n" +
+		"})"
+}
+
+// stepsRoutineSymbol return symbol of routine which runs steps of upgrade or
+// downgrade. Steps are wrapped with transaction when transaction scope is
+// whole upgrade.
+func (filter *CodeGenerateFilter) stepsRoutineSymbol(routineSymbol string) string {
+	if filter.TransactionScope == TransactionScopeUpgrade {
+		return strings.ToLower(routineSymbol[:1]) + routineSymbol[1:] + "Steps"
+	}
+	return routineSymbol
+}
+
+// generateTransactionWrapperRoutine generate routine which runs steps routine
+// in one transaction. Schema is not changed if the transaction failed.
+func (filter *CodeGenerateFilter) generateTransactionWrapperRoutine(fp *os.File, routineSymbol, paramsDecl, argsCode string) (err error) {
+	if filter.TransactionScope != TransactionScopeUpgrade {
+		return nil
+	}
+	_, err = fp.WriteString("func (m *schemaManager) " + routineSymbol + "(" + filter.withCtxParam(paramsDecl) + ") (schemaChanged bool, err error) {\n" +
+		"\tif err = m.withSchemaTx(" + filter.withCtxArg("func(txm *schemaManager) (err error) {") + "\n" +
+		"\t\tschemaChanged, err = txm." + filter.stepsRoutineSymbol(routineSymbol) + "(" + filter.withCtxArg(argsCode) + ")\n" +
+		"\t\treturn\n" +
+		"\t}); nil != err {\n" +
+		"\t\treturn false, err\n" +
+		"\t}\n" +
+		"\treturn\n" +
+		"}\n\n")
+	return
+}
@@ -3,9 +3,11 @@ module github.com/yinyin/go-literal-code-gen
 go 1.12
 
 require (
+	github.com/BurntSushi/toml v1.3.2
 	github.com/yinyin/go-interpolatetext v0.0.0-20181209181057-abd207492187
 	github.com/yinyin/go-run-gofmt v1.0.0
 	gitlab.com/golang-commonmark/linkify v0.0.0-20200225224916-64bca66f6ad3 // indirect
 	gitlab.com/golang-commonmark/markdown v0.0.0-20211110145824-bf3e522c626a
-	golang.org/x/text v0.11.0 // indirect
+	golang.org/x/text v0.11.0
+	gopkg.in/yaml.v3 v3.0.1
 )
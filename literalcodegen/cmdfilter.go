@@ -0,0 +1,162 @@
+package literalcodegen
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultFilterCommandTimeout is the default timeout of filter commands.
+const DefaultFilterCommandTimeout = 30 * time.Second
+
+// FilterCommand is a local command which filters content of entry. Content is
+// written to standard input of command and standard output of command is
+// taken as filtered content.
+type FilterCommand struct {
+	Name    string
+	Args    []string
+	Timeout time.Duration
+}
+
+var filterCommands = make(map[string]*FilterCommand)
+var filterCommandCache = make(map[string]string)
+var filterCommandCacheDir string
+var filterCommandLock sync.Mutex
+
+// RegisterFilterCommand register local command with given name. The command
+// will be stopped when timeout is reached. DefaultFilterCommandTimeout is
+// used if timeout is not positive.
+func RegisterFilterCommand(name string, args []string, timeout time.Duration) (err error) {
+	if name == "" {
+		return fmt.Errorf("name of filter command is required")
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("command line of filter command is required: %s", name)
+	}
+	if timeout <= 0 {
+		timeout = DefaultFilterCommandTimeout
+	}
+	filterCommandLock.Lock()
+	defer filterCommandLock.Unlock()
+	filterCommands[name] = &FilterCommand{
+		Name:    name,
+		Args:    args,
+		Timeout: timeout,
+	}
+	return nil
+}
+
+// SetFilterCommandCacheDir set folder to keep output of filter commands
+// between runs. Output is only cached in memory if folder is empty.
+func SetFilterCommandCacheDir(dirPath string) {
+	filterCommandLock.Lock()
+	defer filterCommandLock.Unlock()
+	filterCommandCacheDir = dirPath
+}
+
+func lookupFilterCommand(name string) *FilterCommand {
+	filterCommandLock.Lock()
+	defer filterCommandLock.Unlock()
+	return filterCommands[name]
+}
+
+// cacheKey compute hash of command line and given input.
+func (cmd *FilterCommand) cacheKey(input string) string {
+	h := sha256.New()
+	for _, arg := range cmd.Args {
+		h.Write([]byte(arg))
+		h.Write([]byte{0})
+	}
+	h.Write([]byte{0})
+	h.Write([]byte(input))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func loadFilterCommandCache(key string) (output string, ok bool) {
+	filterCommandLock.Lock()
+	defer filterCommandLock.Unlock()
+	if output, ok = filterCommandCache[key]; ok {
+		return
+	}
+	if filterCommandCacheDir == "" {
+		return "", false
+	}
+	buf, err := ioutil.ReadFile(filepath.Join(filterCommandCacheDir, key))
+	if nil != err {
+		return "", false
+	}
+	output = string(buf)
+	filterCommandCache[key] = output
+	return output, true
+}
+
+func storeFilterCommandCache(key, output string) {
+	filterCommandLock.Lock()
+	defer filterCommandLock.Unlock()
+	filterCommandCache[key] = output
+	if filterCommandCacheDir == "" {
+		return
+	}
+	if err := os.MkdirAll(filterCommandCacheDir, 0755); nil != err {
+		log.Printf("WARN: cannot create filter command cache folder %s: %v", filterCommandCacheDir, err)
+		return
+	}
+	if err := ioutil.WriteFile(filepath.Join(filterCommandCacheDir, key), []byte(output), 0644); nil != err {
+		log.Printf("WARN: cannot write filter command cache %s: %v", key, err)
+	}
+}
+
+// run execute command with given input and return standard output.
+func (cmd *FilterCommand) run(input string) (output string, err error) {
+	key := cmd.cacheKey(input)
+	if output, ok := loadFilterCommandCache(key); ok {
+		return output, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cmd.Timeout)
+	defer cancel()
+	proc := exec.CommandContext(ctx, cmd.Args[0], cmd.Args[1:]...)
+	proc.Stdin = strings.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	proc.Stdout = &stdout
+	proc.Stderr = &stderr
+	if err = proc.Run(); nil != err {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("timeout after %v", cmd.Timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%v: %s", err, msg)
+		}
+		return "", err
+	}
+	output = stdout.String()
+	storeFilterCommandCache(key, output)
+	return output, nil
+}
+
+// filterWithCommand pipe given content through filter command of entry.
+func (entry *LiteralEntry) filterWithCommand(content []string) (result []string, err error) {
+	cmd := lookupFilterCommand(entry.FilterCommand)
+	if nil == cmd {
+		return nil, fmt.Errorf("%v: filter command (%s) is not registered for entry [%s]", entry.ContentLocation, entry.FilterCommand, entry.TitleText)
+	}
+	text, newLineMode := joinContentLines(content)
+	output, err := cmd.run(text + "\n")
+	if nil != err {
+		return nil, fmt.Errorf("%v: filter command (%s) failed on entry [%s]: %v", entry.ContentLocation, entry.FilterCommand, entry.TitleText, err)
+	}
+	output = strings.TrimSuffix(output, "\n")
+	if newLineMode == contentWithoutNewLine {
+		return strings.Split(output, "\n"), nil
+	}
+	return newLineMode.restore(splitLinesWithNewLine(output)), nil
+}
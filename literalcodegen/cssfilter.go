@@ -0,0 +1,167 @@
+package literalcodegen
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	RegisterLanguageFilter("css", LanguageFilterFunc(cssContentFilter))
+}
+
+// cssPunctuations are characters lexed as single punctuation tokens.
+// Plus and minus signs are part of words since white spaces around them are
+// significant in calc() expressions.
+const cssPunctuations = "{}();:,>~"
+
+func lexCSS(text string) (tokens []minifyToken, err error) {
+	pos := 0
+	for pos < len(text) {
+		start := pos
+		ch := text[pos]
+		var tokenType minifyTokenType
+		switch {
+		case isMinifySpace(ch):
+			pos = scanMinifySpace(text, pos)
+			tokenType = minifyTokenWhitespace
+		case (ch == '/') && (pos+1 < len(text)) && (text[pos+1] == '*'):
+			idx := strings.Index(text[pos+2:], "*/")
+			if idx < 0 {
+				return nil, &ContentPositionError{LineIndex: strings.Count(text[:pos], "\n"), Err: fmt.Errorf("unterminated CSS comment")}
+			}
+			pos = pos + 2 + idx + 2
+			tokenType = minifyTokenComment
+		case (ch == '"') || (ch == '\''):
+			if pos = scanMinifyQuoted(text, pos); pos < 0 {
+				return nil, &ContentPositionError{LineIndex: strings.Count(text[:start], "\n"), Err: fmt.Errorf("unterminated CSS string")}
+			}
+			tokenType = minifyTokenQuoted
+		case strings.IndexByte(cssPunctuations, ch) >= 0:
+			pos++
+			tokenType = minifyTokenPunctuation
+		default:
+			for (pos < len(text)) && !isMinifySpace(text[pos]) && (strings.IndexByte(cssPunctuations+"\"'", text[pos]) < 0) && !strings.HasPrefix(text[pos:], "/*") {
+				pos++
+			}
+			tokenType = minifyTokenWord
+		}
+		tokens = append(tokens, minifyToken{
+			Type: tokenType,
+			Text: text[start:pos],
+		})
+	}
+	return
+}
+
+// cssSeparator return separation for white space between prev and next
+// tokens. White spaces around colons are only dropped in declaration blocks
+// since they are significant in selectors (eg: `.a :hover`).
+func cssSeparator(prev, next *minifyToken, inDeclarations bool, level string) string {
+	if (nil == prev) || (nil == next) {
+		return ""
+	}
+	if prev.isPunctuationOf("{};,") || next.isPunctuationOf("{};,)") {
+		return ""
+	}
+	if level == minifyAggressive {
+		if prev.isPunctuationOf(">~") || next.isPunctuationOf(">~") {
+			return ""
+		}
+		if inDeclarations && (prev.isPunctuationOf(":") || next.isPunctuationOf(":")) {
+			return ""
+		}
+	}
+	return " "
+}
+
+func isKeptCSSComment(tok *minifyToken) bool {
+	return strings.HasPrefix(tok.Text, "/*!")
+}
+
+// cssRuleGroupAtRules are at-rules whose blocks contain rules instead of
+// declarations.
+var cssRuleGroupAtRules = []string{"@media", "@supports", "@document", "@layer", "@container", "@scope", "@starting-style"}
+
+// isCSSRuleGroupPrelude checks if tokens in front of `{` start a block of
+// rules.
+func isCSSRuleGroupPrelude(prelude []minifyToken) bool {
+	for idx := range prelude {
+		tok := &prelude[idx]
+		if (tok.Type == minifyTokenWhitespace) || (tok.Type == minifyTokenComment) {
+			continue
+		}
+		if tok.Type != minifyTokenWord {
+			return false
+		}
+		name := strings.ToLower(tok.Text)
+		for _, atRule := range cssRuleGroupAtRules {
+			if name == atRule {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// isCSSNestedRulePrelude checks if the statement containing token at given
+// index ends with `{`. Such statement in declaration block is selector of
+// nested rule (eg: `& .c :hover { ... }`) instead of a declaration.
+func isCSSNestedRulePrelude(tokens []minifyToken, index int) bool {
+	for idx := index; idx < len(tokens); idx++ {
+		if tok := &tokens[idx]; tok.isPunctuationOf("{};") {
+			return tok.Text == "{"
+		}
+	}
+	return false
+}
+
+func minifyCSS(text, level string) (result string, err error) {
+	tokens, err := lexCSS(text)
+	if nil != err {
+		return
+	}
+	tokens = mergeMinifySpaces(tokens, isKeptCSSComment)
+	var b strings.Builder
+	// declarationBlocks keeps if each opened block contains declarations.
+	var declarationBlocks []bool
+	preludeStart := 0
+	for idx := range tokens {
+		tok := &tokens[idx]
+		switch tok.Type {
+		case minifyTokenWhitespace:
+			prev, next := significantMinifyTokenAround(tokens, idx)
+			inDeclarations := (len(declarationBlocks) > 0) && declarationBlocks[len(declarationBlocks)-1] && !isCSSNestedRulePrelude(tokens, idx)
+			b.WriteString(cssSeparator(prev, next, inDeclarations, level))
+			continue
+		case minifyTokenPunctuation:
+			switch tok.Text {
+			case "{":
+				declarationBlocks = append(declarationBlocks, !isCSSRuleGroupPrelude(tokens[preludeStart:idx]))
+				preludeStart = idx + 1
+			case "}":
+				if len(declarationBlocks) > 0 {
+					declarationBlocks = declarationBlocks[:len(declarationBlocks)-1]
+				}
+				preludeStart = idx + 1
+			case ";":
+				preludeStart = idx + 1
+				if level == minifyAggressive {
+					if _, next := significantMinifyTokenAround(tokens, idx); (nil != next) && next.isPunctuationOf("}") {
+						continue
+					}
+				}
+			}
+		}
+		b.WriteString(tok.Text)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+func cssContentFilter(codeContent []string, filterArgs LanguageFilterArgs) (result []string, err error) {
+	text, newLineMode := joinContentLines(codeContent)
+	if text, err = minifyCSS(text, parseMinifyLevel(filterArgs)); nil != err {
+		return
+	}
+	return newLineMode.restore([]string{text}), nil
+}
@@ -0,0 +1,81 @@
+package literalcodegen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCSSContentFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    []string
+		filterArgs []string
+		expect     string
+	}{
+		{
+			name:    "conservative",
+			content: []string{"/*! keep */", "a > b ,  c ~ d {", "  color : red ;", "  margin : 0 auto ;", "}", "/* drop */"},
+			expect:  "/*! keep */a > b,c ~ d{color : red;margin : 0 auto;}",
+		},
+		{
+			name:       "aggressive",
+			content:    []string{"/*! keep */", "a > b ,  c ~ d {", "  color : red ;", "  margin : 0 auto ;", "}", "/* drop */"},
+			filterArgs: []string{"aggressive"},
+			expect:     "/*! keep */a>b,c~d{color:red;margin:0 auto}",
+		},
+		{
+			name:       "aggressive keeps space before colon of selector",
+			content:    []string{"a :hover { color : red ; }"},
+			filterArgs: []string{"aggressive"},
+			expect:     "a :hover{color:red}",
+		},
+		{
+			name:       "aggressive keeps selectors in rule group",
+			content:    []string{"@media (min-width: 600px) {", "  a :hover .x { color : red ; }", "}"},
+			filterArgs: []string{"aggressive"},
+			expect:     "@media (min-width: 600px){a :hover .x{color:red}}",
+		},
+		{
+			name:       "aggressive keeps selectors of nested rule",
+			content:    []string{".p {", "  color : blue ;", "  & .c :hover { color : red ; }", "}"},
+			filterArgs: []string{"aggressive"},
+			expect:     ".p{color:blue;& .c :hover{color:red}}",
+		},
+		{
+			name:       "quoted text",
+			content:    []string{"a { content: \"  x ; \" ; }"},
+			filterArgs: []string{"aggressive"},
+			expect:     "a{content:\"  x ; \"}",
+		},
+		{
+			name:    "new line on each line",
+			content: []string{"a {\n", "  color: red;\n", "}\n"},
+			expect:  "a{color: red;}\n",
+		},
+	}
+	for _, tt := range tests {
+		result, err := cssContentFilter(tt.content, LanguageFilterArgs(tt.filterArgs))
+		if nil != err {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if text := strings.Join(result, ""); text != tt.expect {
+			t.Errorf("%s: expecting %q but having %q", tt.name, tt.expect, text)
+		}
+	}
+}
+
+func TestCSSContentFilterError(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []string
+	}{
+		{"unterminated string", []string{"a { content: 'x }"}},
+		{"unterminated comment", []string{"a { } /* x"}},
+	}
+	for _, tt := range tests {
+		if _, err := cssContentFilter(tt.content, nil); nil == err {
+			t.Errorf("%s: expecting error", tt.name)
+		}
+	}
+}
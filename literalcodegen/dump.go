@@ -23,6 +23,15 @@ func logLiteralEntry(entry *LiteralEntry) {
 	for idx, val := range entry.Content {
 		log.Printf("   %03d: %v", idx, val)
 	}
+	if len(entry.ReplaceRuleSetNames) > 0 {
+		log.Printf("  > replace-rule-set: %v", entry.ReplaceRuleSetNames)
+	}
+	if entry.Locale != "" {
+		log.Printf("  > locale: %v (variants: %v)", entry.Locale, len(entry.LocaleVariants))
+	}
+	if (entry.SQLDialect != "") || (len(entry.SQLDialectVariants) > 0) {
+		log.Printf("  > sql-dialect: %v (variants: %v)", entry.SQLDialect, len(entry.SQLDialectVariants))
+	}
 	log.Printf("  > replace (%d):", len(entry.replaceRules))
 	for idx, rule := range entry.replaceRules {
 		log.Printf("   %d: %#v", idx, rule)
@@ -49,6 +58,12 @@ func LogLiteralCode(code *LiteralCode) {
 	logLiteralEntries(code.HeadingCodes)
 	log.Printf("# Literal Constants (%d)", len(code.LiteralConstants))
 	logLiteralEntries(code.LiteralConstants)
+	log.Printf("# Variables (%d)", len(code.Variables))
+	for name, value := range code.Variables {
+		log.Printf("- %s = %q", name, value)
+	}
+	log.Printf("# Replace Rule Sets (%d)", len(code.ReplaceRuleSets))
+	logLiteralEntries(code.ReplaceRuleSets)
 }
 
 func logMarkdownAST(tokens []markdown.Token) {
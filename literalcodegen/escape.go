@@ -0,0 +1,45 @@
+package literalcodegen
+
+import (
+	"strings"
+)
+
+// TextEscaperPrefix is the leading text of replace rule item which
+// declares escaper of the previous replace target.
+const TextEscaperPrefix = "escape:"
+
+// EscapeRuntimePackage is the import path of runtime package of built-in escapers.
+const EscapeRuntimePackage = "github.com/yinyin/go-literal-code-gen/literalescape"
+
+var builtinEscapers = map[string]string{
+	"sql-identifier-postgres": "literalescape.QuotePostgreSQLIdentifier",
+	"sql-identifier-mysql":    "literalescape.QuoteMySQLIdentifier",
+	"sql-string":              "literalescape.QuoteSQLString",
+	"html":                    "literalescape.EscapeHTML",
+	"shell":                   "literalescape.QuoteShell",
+}
+
+func isEscaperDeclaration(v string) bool {
+	return strings.HasPrefix(strings.TrimSpace(v), TextEscaperPrefix)
+}
+
+func parseEscaperDeclaration(v string) string {
+	return strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(v), TextEscaperPrefix))
+}
+
+// escaperFunctionName return function name of given escaper.
+// Names of built-in escapers are mapped to functions in runtime package,
+// other names are taken as user-named Go functions.
+func escaperFunctionName(escaper string) string {
+	if fnName, ok := builtinEscapers[escaper]; ok {
+		return fnName
+	}
+	return escaper
+}
+
+func wrapEscaperCode(escaper, code string) string {
+	if (escaper == "") || (code == "") {
+		return code
+	}
+	return escaperFunctionName(escaper) + "(" + code + ")"
+}
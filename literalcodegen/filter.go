@@ -1,62 +1,266 @@
 package literalcodegen
 
 import (
+	"fmt"
+	"os"
+	"sort"
 	"strings"
-	"unicode"
+	"sync"
 )
 
-type languageContentFilter func(codeContent, filterArgs []string) (result []string, err error)
+// LanguageFilter define interface for language specific content filter.
+// Filters are selected by language type of fenced code block.
+type LanguageFilter interface {
+	// FilterContent transform content lines with given filter arguments
+	FilterContent(codeContent []string, filterArgs LanguageFilterArgs) (result []string, err error)
+}
+
+// ContentPositionError indicates an error found by language filter at given
+// position of content. The position will be mapped to location of Markdown
+// input when reported.
+type ContentPositionError struct {
+	// LineIndex is the 0-based index of content line
+	LineIndex int
+
+	// Column is the 1-based column in content line, 0 if not available
+	Column int
+
+	Err error
+}
+
+func (e *ContentPositionError) Error() string {
+	if e.Column > 0 {
+		return fmt.Sprintf("content line %d, column %d: %v", e.LineIndex+1, e.Column, e.Err)
+	}
+	return fmt.Sprintf("content line %d: %v", e.LineIndex+1, e.Err)
+}
+
+// LanguageFilterSupplementGenerator is optionally implemented by LanguageFilter
+// to generate supplement code (eg: parameter lists) after code of entry.
+type LanguageFilterSupplementGenerator interface {
+	// GenerateSupplementCode is invoked after code of given entry is generated
+	GenerateSupplementCode(fp *os.File, entry *LiteralEntry) (err error)
+}
+
+// LanguageContentSplitter is optionally implemented by LanguageFilter which
+// can split content into pieces (eg: SQL statements). Entries with split
+// content are generated as string slices.
+type LanguageContentSplitter interface {
+	// WillSplitContent checks if content is going to be split with given arguments
+	WillSplitContent(filterArgs LanguageFilterArgs) bool
+
+	// SplitContent return filtered content pieces
+	SplitContent(codeContent []string, filterArgs LanguageFilterArgs) (pieces [][]string, err error)
+}
+
+// LanguageFilterFunc is an adapter to use ordinary function as LanguageFilter.
+type LanguageFilterFunc func(codeContent []string, filterArgs LanguageFilterArgs) (result []string, err error)
+
+// FilterContent invokes f(codeContent, filterArgs).
+func (f LanguageFilterFunc) FilterContent(codeContent []string, filterArgs LanguageFilterArgs) (result []string, err error) {
+	return f(codeContent, filterArgs)
+}
+
+// LanguageFilterArgs is the filter arguments given after language type of
+// fenced code block. Arguments are either flags (eg: `keep-comment`) or
+// key-value pairs (eg: `mode=compact`).
+type LanguageFilterArgs []string
+
+// Has checks if given flag is in the arguments.
+func (args LanguageFilterArgs) Has(flag string) bool {
+	for _, arg := range args {
+		if arg == flag {
+			return true
+		}
+	}
+	return false
+}
 
-func parseSQLFilterArgs(filterArgs []string) (removeComments bool) {
-	removeComments = true
-	for _, arg := range filterArgs {
-		if arg == "keep-comment" {
-			removeComments = false
+// Value return value of the last argument with given key.
+func (args LanguageFilterArgs) Value(key string) (value string, ok bool) {
+	prefix := key + "="
+	for _, arg := range args {
+		if strings.HasPrefix(arg, prefix) {
+			value = arg[len(prefix):]
+			ok = true
 		}
 	}
 	return
 }
 
-func sqlContentFilter(codeContent, filterArgs []string) (result []string, err error) {
-	removeComments := parseSQLFilterArgs(filterArgs)
-	lastLineIndex := len(codeContent) - 1
-	notNeedSpace := true
-	for idx, line := range codeContent {
-		if removeComments {
-			stripped := strings.TrimLeftFunc(line, unicode.IsSpace)
-			if strings.HasPrefix(stripped, "--") || strings.HasPrefix(stripped, "/*") {
-				continue
+// ValueOr return value of argument with given key or defaultValue if such
+// argument does not exist.
+func (args LanguageFilterArgs) ValueOr(key, defaultValue string) string {
+	if value, ok := args.Value(key); ok {
+		return value
+	}
+	return defaultValue
+}
+
+// Choice return the last flag in arguments which is one of given choices.
+// The defaultValue will be return if none of the choices is given.
+func (args LanguageFilterArgs) Choice(defaultValue string, choices ...string) string {
+	result := defaultValue
+	for _, arg := range args {
+		for _, c := range choices {
+			if arg == c {
+				result = c
 			}
 		}
-		if idx == lastLineIndex {
-			line = strings.TrimRightFunc(line, func(r rune) bool {
-				if (r == ';') || unicode.IsSpace(r) {
-					return true
-				}
-				return false
-			})
+	}
+	return result
+}
+
+// contentNewLineMode records how content lines carry tailing new line
+// characters so that lines can be restored after filtering.
+type contentNewLineMode int
+
+const (
+	contentWithoutNewLine contentNewLineMode = iota
+	contentWithTailNewLine
+	contentWithNewLineOnEachLine
+)
+
+func joinContentLines(codeContent []string) (text string, mode contentNewLineMode) {
+	lines := make([]string, len(codeContent))
+	eachLine := len(codeContent) > 0
+	for idx, line := range codeContent {
+		if strings.HasSuffix(line, "\n") {
+			line = line[:len(line)-1]
+		} else {
+			eachLine = false
 		}
-		if ch := []rune(line); len(ch) > 0 {
-			firstCh := ch[0]
-			lastCh := ch[len(ch)-1]
-			if (!notNeedSpace) && (firstCh >= 'A') && (firstCh <= 'Z') {
-				line = " " + line
+		lines[idx] = line
+	}
+	if eachLine {
+		mode = contentWithNewLineOnEachLine
+	} else if lastIndex := len(codeContent) - 1; (lastIndex >= 0) && strings.HasSuffix(codeContent[lastIndex], "\n") {
+		mode = contentWithTailNewLine
+	}
+	return strings.Join(lines, "\n"), mode
+}
+
+func (mode contentNewLineMode) restore(lines []string) []string {
+	switch mode {
+	case contentWithNewLineOnEachLine:
+		for idx := range lines {
+			if !strings.HasSuffix(lines[idx], "\n") {
+				lines[idx] = lines[idx] + "\n"
 			}
-			notNeedSpace = ((lastCh == '(') || (lastCh == ','))
 		}
-		result = append(result, line)
+	case contentWithTailNewLine:
+		if lastIndex := len(lines) - 1; (lastIndex >= 0) && !strings.HasSuffix(lines[lastIndex], "\n") {
+			lines[lastIndex] = lines[lastIndex] + "\n"
+		}
+	}
+	return lines
+}
+
+// splitLinesWithNewLine split text into lines. Each line except the last
+// one keeps the tailing new line character.
+func splitLinesWithNewLine(text string) (lines []string) {
+	lines = strings.Split(text, "\n")
+	lastIndex := len(lines) - 1
+	for idx := 0; idx < lastIndex; idx++ {
+		lines[idx] = lines[idx] + "\n"
 	}
 	return
 }
 
-func runLanaguageFilter(langType string, codeContent, filterArgs []string) (result []string, err error) {
-	var filterCallable languageContentFilter
-	switch langType {
-	case "sql":
-		filterCallable = sqlContentFilter
+type languageFilterRegistration struct {
+	filter  LanguageFilter
+	enabled bool
+}
+
+var languageFilterRegistryLock sync.Mutex
+var languageFilterRegistry = make(map[string]*languageFilterRegistration)
+
+// RegisterLanguageFilter register filter for given language type.
+// Registered filter is enabled. Filter registered before will be replaced.
+func RegisterLanguageFilter(lang string, f LanguageFilter) {
+	languageFilterRegistryLock.Lock()
+	defer languageFilterRegistryLock.Unlock()
+	languageFilterRegistry[lang] = &languageFilterRegistration{
+		filter:  f,
+		enabled: true,
+	}
+}
+
+// RegisterOptionalLanguageFilter register filter for given language type.
+// The filter is disabled until enabled with EnableLanguageFilter.
+func RegisterOptionalLanguageFilter(lang string, f LanguageFilter) {
+	languageFilterRegistryLock.Lock()
+	defer languageFilterRegistryLock.Unlock()
+	languageFilterRegistry[lang] = &languageFilterRegistration{
+		filter:  f,
+		enabled: false,
+	}
+}
+
+func setLanguageFilterEnabled(lang string, enabled bool) (err error) {
+	languageFilterRegistryLock.Lock()
+	defer languageFilterRegistryLock.Unlock()
+	reg, ok := languageFilterRegistry[lang]
+	if !ok {
+		return fmt.Errorf("language filter not registered: %v", lang)
 	}
-	if nil == filterCallable {
+	reg.enabled = enabled
+	return nil
+}
+
+// EnableLanguageFilter enable registered filter of given language type.
+func EnableLanguageFilter(lang string) (err error) {
+	return setLanguageFilterEnabled(lang, true)
+}
+
+// DisableLanguageFilter disable registered filter of given language type.
+func DisableLanguageFilter(lang string) (err error) {
+	return setLanguageFilterEnabled(lang, false)
+}
+
+// RegisteredLanguageFilters return sorted language types of registered filters.
+func RegisteredLanguageFilters() (langTypes []string) {
+	languageFilterRegistryLock.Lock()
+	defer languageFilterRegistryLock.Unlock()
+	for lang := range languageFilterRegistry {
+		langTypes = append(langTypes, lang)
+	}
+	sort.Strings(langTypes)
+	return
+}
+
+func lookupLanguageFilter(langType string) LanguageFilter {
+	languageFilterRegistryLock.Lock()
+	defer languageFilterRegistryLock.Unlock()
+	if reg, ok := languageFilterRegistry[langType]; ok && reg.enabled {
+		return reg.filter
+	}
+	return nil
+}
+
+func runLanguageFilterSupplementGenerator(fp *os.File, entry *LiteralEntry) (err error) {
+	if entry.DisableLanguageFilter {
+		return nil
+	}
+	generator, ok := lookupLanguageFilter(entry.LanguageType).(LanguageFilterSupplementGenerator)
+	if !ok {
+		return nil
+	}
+	return generator.GenerateSupplementCode(fp, entry)
+}
+
+func lookupLanguageContentSplitter(langType string, filterArgs []string) LanguageContentSplitter {
+	splitter, ok := lookupLanguageFilter(langType).(LanguageContentSplitter)
+	if (!ok) || !splitter.WillSplitContent(LanguageFilterArgs(filterArgs)) {
+		return nil
+	}
+	return splitter
+}
+
+func runLanaguageFilter(langType string, codeContent, filterArgs []string) (result []string, err error) {
+	filter := lookupLanguageFilter(langType)
+	if nil == filter {
 		return codeContent, nil
 	}
-	return filterCallable(codeContent, filterArgs)
+	return filter.FilterContent(codeContent, LanguageFilterArgs(filterArgs))
 }
@@ -41,13 +41,15 @@ func generateHeadingCode(fp *os.File, entries []*LiteralEntry) (err error) {
 	return nil
 }
 
-func writeSimpleLiteralText(fp *os.File, line string, currentLineIndex, lastLineIndex int) (err error) {
+func writeSimpleLiteralText(fp *os.File, line string, currentLineIndex, lastLineIndex int, lastLineTail string) (err error) {
 	codeLine := strconv.Quote(line)
 	if currentLineIndex != 0 {
 		codeLine = "\t\t" + codeLine
 	}
 	if currentLineIndex != lastLineIndex {
 		codeLine = codeLine + " +"
+	} else {
+		codeLine = codeLine + lastLineTail
 	}
 	codeLine = codeLine + "\n"
 	if _, err = fp.WriteString(codeLine); nil != err {
@@ -78,7 +80,7 @@ func appendLiteralCode(codeLine, codeText string, hasCode bool) (string, bool) {
 	return codeLine, true
 }
 
-func writeReplacedLiteralCode(fp *os.File, lineSegs []*ReplaceResult, currentLineIndex, lastLineIndex int) (err error) {
+func writeReplacedLiteralCode(fp *os.File, lineSegs []*ReplaceResult, currentLineIndex, lastLineIndex int, lastLineTail string) (err error) {
 	var codeLine string
 	if currentLineIndex != 0 {
 		codeLine = "\t\t"
@@ -94,6 +96,8 @@ func writeReplacedLiteralCode(fp *os.File, lineSegs []*ReplaceResult, currentLin
 	}
 	if currentLineIndex != lastLineIndex {
 		codeLine = codeLine + " +"
+	} else {
+		codeLine = codeLine + lastLineTail
 	}
 	codeLine = codeLine + "\n"
 	if _, err = fp.WriteString(codeLine); nil != err {
@@ -102,7 +106,64 @@ func writeReplacedLiteralCode(fp *os.File, lineSegs []*ReplaceResult, currentLin
 	return
 }
 
+func writeLiteralContent(fp *os.File, content []string, lastLineTail string) (err error) {
+	lastLineIndex := len(content) - 1
+	for idx, line := range content {
+		if err = writeSimpleLiteralText(fp, line, idx, lastLineIndex, lastLineTail); nil != err {
+			return
+		}
+	}
+	return nil
+}
+
+func writeBuilderContent(fp *os.File, entry *LiteralEntry, paramTypes map[string]string, content []string, lastLineTail string) (err error) {
+	lastLineIndex := len(content) - 1
+	for idx, line := range content {
+		replaced, err := doReplace(entry.replaceRules, line)
+		if nil != err {
+			return err
+		}
+		if err = entry.resolveReplaceResults(paramTypes, replaced); nil != err {
+			return err
+		}
+		if nil == replaced {
+			if err = writeSimpleLiteralText(fp, entry.applyLineEnding(line), idx, lastLineIndex, lastLineTail); nil != err {
+				return err
+			}
+		} else {
+			for _, lineSeg := range replaced {
+				lineSeg.PrefixLiteral = entry.applyLineEnding(lineSeg.PrefixLiteral)
+				lineSeg.SuffixLiteral = entry.applyLineEnding(lineSeg.SuffixLiteral)
+			}
+			if err = writeReplacedLiteralCode(fp, replaced, idx, lastLineIndex, lastLineTail); nil != err {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func generateLiteralCodeAsConstSlice(fp *os.File, entry *LiteralEntry, pieces [][]string) (err error) {
+	if _, err = fp.WriteString("var " + entry.Name + " = []string{\n"); nil != err {
+		return
+	}
+	for _, piece := range pieces {
+		if err = writeLiteralContent(fp, entry.applyLineEndingToContent(piece), ","); nil != err {
+			return
+		}
+	}
+	_, err = fp.WriteString("}\n\n")
+	return
+}
+
 func generateLiteralCodeAsConst(fp *os.File, entry *LiteralEntry) (err error) {
+	pieces, err := entry.FilteredContentPieces()
+	if nil != err {
+		return
+	}
+	if nil != pieces {
+		return generateLiteralCodeAsConstSlice(fp, entry, pieces)
+	}
 	codeLine := "const " + entry.Name + " = "
 	if _, err = fp.WriteString(codeLine); nil != err {
 		return
@@ -111,19 +172,39 @@ func generateLiteralCodeAsConst(fp *os.File, entry *LiteralEntry) (err error) {
 	if nil != err {
 		return
 	}
-	lastLineIndex := len(content) - 1
-	for idx, line := range content {
-		if err = writeSimpleLiteralText(fp, line, idx, lastLineIndex); nil != err {
-			return
-		}
+	if err = writeLiteralContent(fp, entry.applyLineEndingToContent(content), ""); nil != err {
+		return
+	}
+	_, err = fp.WriteString("\n")
+	return
+}
+
+func generateLiteralCodeAsRegexp(fp *os.File, entry *LiteralEntry) (err error) {
+	pattern, err := entry.regexpPattern()
+	if nil != err {
+		return
+	}
+	if _, err = fp.WriteString("var " + entry.Name + " = regexp.MustCompile("); nil != err {
+		return
+	}
+	if err = writeLiteralContent(fp, []string{pattern}, ")"); nil != err {
+		return
 	}
 	_, err = fp.WriteString("\n")
 	return
 }
 
 func generateLiteralCodeAsBuilder(fp *os.File, entry *LiteralEntry) (err error) {
+	pieces, err := entry.FilteredContentPieces()
+	if nil != err {
+		return
+	}
+	resultType := "string"
+	if nil != pieces {
+		resultType = "[]string"
+	}
 	var codeLine string
-	codeLine = "func " + entry.Name + "(" + strings.Join(entry.Parameters, ", ") + ") string {\n"
+	codeLine = "func " + entry.Name + "(" + strings.Join(entry.Parameters, ", ") + ") " + resultType + " {\n"
 	if _, err = fp.WriteString(codeLine); nil != err {
 		return
 	}
@@ -132,6 +213,19 @@ func generateLiteralCodeAsBuilder(fp *os.File, entry *LiteralEntry) (err error)
 			return
 		}
 	}
+	paramTypes := parseParameterTypes(entry.Parameters)
+	if nil != pieces {
+		if _, err = fp.WriteString("\treturn []string{\n"); nil != err {
+			return
+		}
+		for _, piece := range pieces {
+			if err = writeBuilderContent(fp, entry, paramTypes, piece, ","); nil != err {
+				return
+			}
+		}
+		_, err = fp.WriteString("\t}\n}\n\n")
+		return
+	}
 	codeLine = "\treturn "
 	if _, err = fp.WriteString(codeLine); nil != err {
 		return
@@ -140,26 +234,26 @@ func generateLiteralCodeAsBuilder(fp *os.File, entry *LiteralEntry) (err error)
 	if nil != err {
 		return
 	}
-	lastLineIndex := len(content) - 1
-	for idx, line := range content {
-		replaced, err := doReplace(entry.replaceRules, line)
-		if nil != err {
-			return err
-		}
-		if nil == replaced {
-			if err = writeSimpleLiteralText(fp, line, idx, lastLineIndex); nil != err {
-				return err
-			}
-		} else {
-			if err = writeReplacedLiteralCode(fp, replaced, idx, lastLineIndex); nil != err {
-				return err
-			}
-		}
+	if err = writeBuilderContent(fp, entry, paramTypes, content, ""); nil != err {
+		return
 	}
 	_, err = fp.WriteString("}\n\n")
 	return
 }
 
+// nameVariantEntries derive names of locale and SQL dialect variants from
+// names of their entries.
+func nameVariantEntries(entries []*LiteralEntry) {
+	for _, entry := range entries {
+		if entry.isLocalized() {
+			entry.nameLocaleVariants()
+		}
+		if entry.hasSQLDialectVariants() {
+			entry.nameSQLDialectVariants()
+		}
+	}
+}
+
 func generateLiteralCodes(fp *os.File, entries []*LiteralEntry) (err error) {
 	for _, entry := range entries {
 		if (entry.Name == "") || (entry.Name == "-") {
@@ -175,18 +269,42 @@ func generateLiteralCodes(fp *os.File, entries []*LiteralEntry) (err error) {
 			err = generateLiteralCodeAsConst(fp, entry)
 		case TranslateAsBuilder:
 			err = generateLiteralCodeAsBuilder(fp, entry)
+		case TranslateAsRegexp:
+			err = generateLiteralCodeAsRegexp(fp, entry)
+		case TranslateAsTemplate:
+			err = generateLiteralCodeAsTemplate(fp, entry)
 		default:
 			err = fmt.Errorf("unknown literal code generating mode: %d (%#v)", entry.TranslationMode, entry)
 		}
 		if nil != err {
 			return
 		}
+		if (entry.TranslationMode == TranslateAsConst) || (entry.TranslationMode == TranslateAsBuilder) {
+			if err = runLanguageFilterSupplementGenerator(fp, entry); nil != err {
+				return
+			}
+			if err = generateLocaleLookup(fp, entry); nil != err {
+				return
+			}
+			if err = generateSQLDialectSelector(fp, entry); nil != err {
+				return
+			}
+		}
 	}
 	return nil
 }
 
 // GenerateGoCodeFile generate code and save to given file path.
 func GenerateGoCodeFile(path string, code *LiteralCode, genDoNotEdit bool, externalFilter ExternalFilter) (err error) {
+	if nil != externalFilter {
+		if err = externalFilter.PreCodeGenerate(code.LiteralConstants); nil != err {
+			return
+		}
+	}
+	nameVariantEntries(code.LiteralConstants)
+	if err = ValidateGoCode(code.LiteralConstants); nil != err {
+		return
+	}
 	fp, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
 	if nil != err {
 		return
@@ -200,11 +318,6 @@ func GenerateGoCodeFile(path string, code *LiteralCode, genDoNotEdit bool, exter
 	if err = generateHeadingCode(fp, code.HeadingCodes); nil != err {
 		return
 	}
-	if nil != externalFilter {
-		if err = externalFilter.PreCodeGenerate(code.LiteralConstants); nil != err {
-			return
-		}
-	}
 	if err = generateLiteralCodes(fp, code.LiteralConstants); nil != err {
 		return
 	}
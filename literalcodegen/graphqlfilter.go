@@ -0,0 +1,92 @@
+package literalcodegen
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	RegisterLanguageFilter(LanguageGraphQL, &graphQLLanguageFilter{})
+}
+
+// LanguageGraphQL is the language type of GraphQL content.
+const LanguageGraphQL = "graphql"
+
+// Symbol suffixes of generated GraphQL operation lists.
+const (
+	GraphQLOperationNamesSymbolSuffix     = "OperationNames"
+	GraphQLOperationVariablesSymbolSuffix = "OperationVariables"
+)
+
+type graphQLLanguageFilter struct{}
+
+// FilterContent validate GraphQL content and strip comments and
+// insignificant white spaces unless `keep-format` is given. Line breaks are
+// kept in `keep-format` mode so that the output is exactly the validated
+// text (line comments would swallow following lines otherwise).
+func (f *graphQLLanguageFilter) FilterContent(codeContent []string, filterArgs LanguageFilterArgs) (result []string, err error) {
+	text, newLineMode := joinContentLines(codeContent)
+	tokens, _, err := parseGraphQL(text)
+	if nil != err {
+		return
+	}
+	if filterArgs.Has("keep-format") {
+		return newLineMode.restore(splitLinesWithNewLine(text)), nil
+	}
+	return newLineMode.restore([]string{joinGraphQLTokens(tokens)}), nil
+}
+
+// GenerateSupplementCode generate operation name and variable lists when
+// `list-operations` is given.
+func (f *graphQLLanguageFilter) GenerateSupplementCode(fp *os.File, entry *LiteralEntry) (err error) {
+	if !LanguageFilterArgs(entry.LanguageFilterArgs).Has("list-operations") {
+		return nil
+	}
+	operations, err := entry.GraphQLOperations()
+	if nil != err {
+		return
+	}
+	namesSymbol := entry.Name + GraphQLOperationNamesSymbolSuffix
+	variablesSymbol := entry.Name + GraphQLOperationVariablesSymbolSuffix
+	var b strings.Builder
+	b.WriteString("// " + namesSymbol + " lists operations defined in " + entry.Name + ":\n//\n")
+	quotedNames := make([]string, 0, len(operations))
+	for _, op := range operations {
+		b.WriteString("//\t" + op.Signature() + "\n")
+		quotedNames = append(quotedNames, strconv.Quote(op.Name))
+	}
+	b.WriteString("var " + namesSymbol + " = []string{" + strings.Join(quotedNames, ", ") + "}\n\n")
+	b.WriteString("// " + variablesSymbol + " maps operation names to variable names in definition order.\n")
+	b.WriteString("var " + variablesSymbol + " = map[string][]string{\n")
+	for _, op := range operations {
+		quotedVariables := make([]string, 0, len(op.Variables))
+		for _, name := range op.VariableNames() {
+			quotedVariables = append(quotedVariables, strconv.Quote(name))
+		}
+		b.WriteString(strconv.Quote(op.Name) + ": {" + strings.Join(quotedVariables, ", ") + "},\n")
+	}
+	b.WriteString("}\n\n")
+	_, err = fp.WriteString(b.String())
+	return
+}
+
+// ParseGraphQLOperations parse given GraphQL content lines and return the
+// defined operations.
+func ParseGraphQLOperations(codeContent []string) (operations []*GraphQLOperation, err error) {
+	text, _ := joinContentLines(codeContent)
+	_, operations, err = parseGraphQL(text)
+	return
+}
+
+// GraphQLOperations return operations defined in GraphQL content of entry.
+// Empty result is returned if entry content is not GraphQL.
+func (entry *LiteralEntry) GraphQLOperations() (operations []*GraphQLOperation, err error) {
+	if entry.LanguageType != LanguageGraphQL {
+		return nil, nil
+	}
+	if operations, err = ParseGraphQLOperations(entry.Content); nil != err {
+		return nil, entry.languageFilterError(err)
+	}
+	return
+}
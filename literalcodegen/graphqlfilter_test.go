@@ -0,0 +1,58 @@
+package literalcodegen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGraphQLFilterContent(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    []string
+		filterArgs []string
+		expect     string
+	}{
+		{
+			name:    "strip comments and white spaces",
+			content: []string{"# fetch user", "query Q($id: ID!) {", "  user(id: $id) { id, name }", "}"},
+			expect:  "query Q($id:ID!){user(id:$id){id name}}",
+		},
+		{
+			name:       "keep format without new line option",
+			content:    []string{"# fetch user", "query Q($id: ID!) {", "  user(id: $id) { id }", "}"},
+			filterArgs: []string{"keep-format"},
+			expect:     "# fetch user\nquery Q($id: ID!) {\n  user(id: $id) { id }\n}",
+		},
+		{
+			name:       "keep format with new line on each line",
+			content:    []string{"# fetch user\n", "{ user { id } }\n"},
+			filterArgs: []string{"keep-format"},
+			expect:     "# fetch user\n{ user { id } }\n",
+		},
+	}
+	filter := &graphQLLanguageFilter{}
+	for _, tt := range tests {
+		result, err := filter.FilterContent(tt.content, LanguageFilterArgs(tt.filterArgs))
+		if nil != err {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		text := strings.Join(result, "")
+		if text != tt.expect {
+			t.Errorf("%s: expecting %q but having %q", tt.name, tt.expect, text)
+			continue
+		}
+		if _, _, err = parseGraphQL(text); nil != err {
+			t.Errorf("%s: output is not valid GraphQL: %v", tt.name, err)
+		}
+	}
+}
+
+func TestGraphQLFilterContentError(t *testing.T) {
+	filter := &graphQLLanguageFilter{}
+	for _, filterArgs := range [][]string{nil, {"keep-format"}} {
+		if _, err := filter.FilterContent([]string{"query Q { a(x: $x) }"}, LanguageFilterArgs(filterArgs)); nil == err {
+			t.Errorf("expecting error of undefined variable (args: %v)", filterArgs)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package literalcodegen
+
+import (
+	"fmt"
+	"strings"
+)
+
+type graphQLTokenType int
+
+const (
+	graphQLTokenName graphQLTokenType = iota
+	graphQLTokenInt
+	graphQLTokenFloat
+	graphQLTokenString
+	graphQLTokenBlockString
+	graphQLTokenPunctuator
+	graphQLTokenEOF
+)
+
+type graphQLToken struct {
+	Type graphQLTokenType
+	Text string
+
+	// Offset is the position of token in content text
+	Offset int
+}
+
+func (tok *graphQLToken) isPunctuator(text string) bool {
+	return (tok.Type == graphQLTokenPunctuator) && (tok.Text == text)
+}
+
+func (tok *graphQLToken) isName(text string) bool {
+	return (tok.Type == graphQLTokenName) && (tok.Text == text)
+}
+
+func (tok *graphQLToken) describe() string {
+	if tok.Type == graphQLTokenEOF {
+		return "end of content"
+	}
+	return "\"" + tok.Text + "\""
+}
+
+// graphQLLexer splits GraphQL text into significant tokens. Ignored tokens
+// (white spaces, commas and comments) are dropped.
+type graphQLLexer struct {
+	text   string
+	pos    int
+	tokens []graphQLToken
+}
+
+func newGraphQLContentError(text string, offset int, format string, a ...interface{}) error {
+	prefix := text[:offset]
+	return &ContentPositionError{
+		LineIndex: strings.Count(prefix, "\n"),
+		Column:    len(prefix) - strings.LastIndexByte(prefix, '\n'),
+		Err:       fmt.Errorf(format, a...),
+	}
+}
+
+func (lexer *graphQLLexer) errorf(format string, a ...interface{}) error {
+	return newGraphQLContentError(lexer.text, lexer.pos, format, a...)
+}
+
+func (lexer *graphQLLexer) emit(tokenType graphQLTokenType, start int) {
+	lexer.tokens = append(lexer.tokens, graphQLToken{
+		Type:   tokenType,
+		Text:   lexer.text[start:lexer.pos],
+		Offset: start,
+	})
+}
+
+func isGraphQLNameStart(ch byte) bool {
+	return ((ch >= 'a') && (ch <= 'z')) || ((ch >= 'A') && (ch <= 'Z')) || (ch == '_')
+}
+
+func isGraphQLDigit(ch byte) bool {
+	return (ch >= '0') && (ch <= '9')
+}
+
+func (lexer *graphQLLexer) scanDigits() int {
+	start := lexer.pos
+	for (lexer.pos < len(lexer.text)) && isGraphQLDigit(lexer.text[lexer.pos]) {
+		lexer.pos++
+	}
+	return lexer.pos - start
+}
+
+func (lexer *graphQLLexer) scanNumber(start int) (err error) {
+	if lexer.text[lexer.pos] == '-' {
+		lexer.pos++
+	}
+	if lexer.scanDigits() == 0 {
+		return lexer.errorf("expecting digit")
+	}
+	tokenType := graphQLTokenInt
+	if (lexer.pos < len(lexer.text)) && (lexer.text[lexer.pos] == '.') {
+		lexer.pos++
+		if lexer.scanDigits() == 0 {
+			return lexer.errorf("expecting digit after decimal point")
+		}
+		tokenType = graphQLTokenFloat
+	}
+	if (lexer.pos < len(lexer.text)) && ((lexer.text[lexer.pos] == 'e') || (lexer.text[lexer.pos] == 'E')) {
+		lexer.pos++
+		if (lexer.pos < len(lexer.text)) && ((lexer.text[lexer.pos] == '+') || (lexer.text[lexer.pos] == '-')) {
+			lexer.pos++
+		}
+		if lexer.scanDigits() == 0 {
+			return lexer.errorf("expecting digit in exponent")
+		}
+		tokenType = graphQLTokenFloat
+	}
+	if (lexer.pos < len(lexer.text)) && (isGraphQLNameStart(lexer.text[lexer.pos]) || (lexer.text[lexer.pos] == '.')) {
+		return lexer.errorf("invalid number")
+	}
+	lexer.emit(tokenType, start)
+	return nil
+}
+
+func (lexer *graphQLLexer) scanString(start int) (err error) {
+	if strings.HasPrefix(lexer.text[lexer.pos:], "\"\"\"") {
+		for lexer.pos += 3; lexer.pos < len(lexer.text); lexer.pos++ {
+			if strings.HasPrefix(lexer.text[lexer.pos:], "\\\"\"\"") {
+				lexer.pos += 3
+			} else if strings.HasPrefix(lexer.text[lexer.pos:], "\"\"\"") {
+				lexer.pos += 3
+				lexer.emit(graphQLTokenBlockString, start)
+				return nil
+			}
+		}
+		lexer.pos = start
+		return lexer.errorf("unterminated block string")
+	}
+	for lexer.pos++; lexer.pos < len(lexer.text); lexer.pos++ {
+		switch lexer.text[lexer.pos] {
+		case '\\':
+			lexer.pos++
+		case '\n', '\r':
+			lexer.pos = start
+			return lexer.errorf("unterminated string")
+		case '"':
+			lexer.pos++
+			lexer.emit(graphQLTokenString, start)
+			return nil
+		}
+	}
+	lexer.pos = start
+	return lexer.errorf("unterminated string")
+}
+
+func (lexer *graphQLLexer) run() (err error) {
+	for lexer.pos < len(lexer.text) {
+		start := lexer.pos
+		ch := lexer.text[lexer.pos]
+		switch {
+		case (ch == ' ') || (ch == '\t') || (ch == '\n') || (ch == '\r') || (ch == ','):
+			lexer.pos++
+		case strings.HasPrefix(lexer.text[lexer.pos:], "\xef\xbb\xbf"):
+			lexer.pos += 3
+		case ch == '#':
+			for (lexer.pos < len(lexer.text)) && (lexer.text[lexer.pos] != '\n') && (lexer.text[lexer.pos] != '\r') {
+				lexer.pos++
+			}
+		case isGraphQLNameStart(ch):
+			for (lexer.pos < len(lexer.text)) && (isGraphQLNameStart(lexer.text[lexer.pos]) || isGraphQLDigit(lexer.text[lexer.pos])) {
+				lexer.pos++
+			}
+			lexer.emit(graphQLTokenName, start)
+		case (ch == '-') || isGraphQLDigit(ch):
+			if err = lexer.scanNumber(start); nil != err {
+				return
+			}
+		case ch == '"':
+			if err = lexer.scanString(start); nil != err {
+				return
+			}
+		case strings.HasPrefix(lexer.text[lexer.pos:], "..."):
+			lexer.pos += 3
+			lexer.emit(graphQLTokenPunctuator, start)
+		case strings.IndexByte("!$&():=@[]{|}", ch) >= 0:
+			lexer.pos++
+			lexer.emit(graphQLTokenPunctuator, start)
+		default:
+			return lexer.errorf("unexpected character %q", ch)
+		}
+	}
+	lexer.tokens = append(lexer.tokens, graphQLToken{
+		Type:   graphQLTokenEOF,
+		Offset: len(lexer.text),
+	})
+	return nil
+}
+
+func lexGraphQL(text string) (tokens []graphQLToken, err error) {
+	lexer := &graphQLLexer{
+		text: text,
+	}
+	if err = lexer.run(); nil != err {
+		return
+	}
+	return lexer.tokens, nil
+}
+
+func isGraphQLWordToken(tok *graphQLToken) bool {
+	switch tok.Type {
+	case graphQLTokenName, graphQLTokenInt, graphQLTokenFloat:
+		return true
+	}
+	return false
+}
+
+// joinGraphQLTokens join tokens with minimal white spaces.
+func joinGraphQLTokens(tokens []graphQLToken) string {
+	var b strings.Builder
+	var prev *graphQLToken
+	for idx := range tokens {
+		tok := &tokens[idx]
+		if tok.Type == graphQLTokenEOF {
+			break
+		}
+		if nil != prev {
+			if (isGraphQLWordToken(prev) && isGraphQLWordToken(tok)) ||
+				((prev.Type == graphQLTokenString) && ((tok.Type == graphQLTokenString) || (tok.Type == graphQLTokenBlockString))) {
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(tok.Text)
+		prev = tok
+	}
+	return b.String()
+}
@@ -0,0 +1,417 @@
+package literalcodegen
+
+import (
+	"strings"
+)
+
+// GraphQLVariable is a variable definition of GraphQL operation.
+type GraphQLVariable struct {
+	Name         string
+	Type         string
+	DefaultValue string
+}
+
+// GraphQLOperation is an operation defined in GraphQL content.
+type GraphQLOperation struct {
+	// Type is one of `query`, `mutation` or `subscription`
+	Type string
+
+	// Name is the operation name, empty for anonymous operation
+	Name string
+
+	Variables []*GraphQLVariable
+
+	// offset is the position of operation in content text
+	offset int
+}
+
+// Signature return operation type, name and variable definitions in GraphQL
+// syntax (eg: `query GetUser($id: ID!)`).
+func (op *GraphQLOperation) Signature() string {
+	result := op.Type
+	if op.Name != "" {
+		result = result + " " + op.Name
+	}
+	if len(op.Variables) == 0 {
+		return result
+	}
+	defs := make([]string, 0, len(op.Variables))
+	for _, v := range op.Variables {
+		def := "$" + v.Name + ": " + v.Type
+		if v.DefaultValue != "" {
+			def = def + " = " + v.DefaultValue
+		}
+		defs = append(defs, def)
+	}
+	return result + "(" + strings.Join(defs, ", ") + ")"
+}
+
+// VariableNames return names of variables in definition order.
+func (op *GraphQLOperation) VariableNames() (names []string) {
+	for _, v := range op.Variables {
+		names = append(names, v.Name)
+	}
+	return
+}
+
+// graphQLParser is a recursive descent parser of GraphQL executable
+// documents. Only syntax and variable usages are checked since schema is not
+// available on generation.
+type graphQLParser struct {
+	text   string
+	tokens []graphQLToken
+	pos    int
+
+	operations []*GraphQLOperation
+	fragments  map[string]struct{}
+
+	// usedVariables collects variables referenced in current operation
+	usedVariables []*graphQLToken
+}
+
+func (parser *graphQLParser) peek() *graphQLToken {
+	return &parser.tokens[parser.pos]
+}
+
+func (parser *graphQLParser) next() *graphQLToken {
+	tok := &parser.tokens[parser.pos]
+	if tok.Type != graphQLTokenEOF {
+		parser.pos++
+	}
+	return tok
+}
+
+func (parser *graphQLParser) errorAt(tok *graphQLToken, format string, a ...interface{}) error {
+	return newGraphQLContentError(parser.text, tok.Offset, format, a...)
+}
+
+func (parser *graphQLParser) expectPunctuator(text string) (err error) {
+	if tok := parser.next(); !tok.isPunctuator(text) {
+		return parser.errorAt(tok, "expecting \"%s\" but having %s", text, tok.describe())
+	}
+	return nil
+}
+
+func (parser *graphQLParser) skipPunctuator(text string) bool {
+	if parser.peek().isPunctuator(text) {
+		parser.pos++
+		return true
+	}
+	return false
+}
+
+func (parser *graphQLParser) expectName() (tok *graphQLToken, err error) {
+	if tok = parser.next(); tok.Type != graphQLTokenName {
+		return nil, parser.errorAt(tok, "expecting name but having %s", tok.describe())
+	}
+	return tok, nil
+}
+
+func (parser *graphQLParser) parseDocument() (err error) {
+	if parser.peek().Type == graphQLTokenEOF {
+		return parser.errorAt(parser.peek(), "empty document")
+	}
+	for parser.peek().Type != graphQLTokenEOF {
+		tok := parser.peek()
+		switch {
+		case tok.isPunctuator("{"):
+			err = parser.parseOperation("query", false)
+		case tok.isName("query"), tok.isName("mutation"), tok.isName("subscription"):
+			err = parser.parseOperation(tok.Text, true)
+		case tok.isName("fragment"):
+			err = parser.parseFragment()
+		default:
+			err = parser.errorAt(tok, "expecting operation or fragment definition but having %s", tok.describe())
+		}
+		if nil != err {
+			return
+		}
+	}
+	return parser.checkOperationNames()
+}
+
+func (parser *graphQLParser) checkOperationNames() (err error) {
+	names := make(map[string]struct{})
+	for _, op := range parser.operations {
+		if op.Name == "" {
+			if len(parser.operations) > 1 {
+				return newGraphQLContentError(parser.text, op.offset, "anonymous operation must be the only operation in document")
+			}
+			continue
+		}
+		if _, ok := names[op.Name]; ok {
+			return newGraphQLContentError(parser.text, op.offset, "duplicated operation name: %s", op.Name)
+		}
+		names[op.Name] = struct{}{}
+	}
+	return nil
+}
+
+func (parser *graphQLParser) parseOperation(opType string, allowDefinitions bool) (err error) {
+	op := &GraphQLOperation{
+		Type:   opType,
+		offset: parser.peek().Offset,
+	}
+	parser.usedVariables = nil
+	if allowDefinitions {
+		parser.pos++
+		if parser.peek().Type == graphQLTokenName {
+			op.Name = parser.next().Text
+		}
+		if parser.skipPunctuator("(") {
+			if op.Variables, err = parser.parseVariableDefinitions(); nil != err {
+				return
+			}
+		}
+		if err = parser.parseDirectives(false); nil != err {
+			return
+		}
+	}
+	if err = parser.parseSelectionSet(); nil != err {
+		return
+	}
+	defined := make(map[string]struct{})
+	for _, v := range op.Variables {
+		defined[v.Name] = struct{}{}
+	}
+	for _, tok := range parser.usedVariables {
+		if _, ok := defined[tok.Text]; !ok {
+			return parser.errorAt(tok, "variable $%s is not defined in operation", tok.Text)
+		}
+	}
+	parser.operations = append(parser.operations, op)
+	return nil
+}
+
+func (parser *graphQLParser) parseVariableDefinitions() (variables []*GraphQLVariable, err error) {
+	for !parser.skipPunctuator(")") {
+		if err = parser.expectPunctuator("$"); nil != err {
+			return
+		}
+		nameToken, err := parser.expectName()
+		if nil != err {
+			return nil, err
+		}
+		for _, v := range variables {
+			if v.Name == nameToken.Text {
+				return nil, parser.errorAt(nameToken, "duplicated variable: $%s", nameToken.Text)
+			}
+		}
+		if err = parser.expectPunctuator(":"); nil != err {
+			return nil, err
+		}
+		v := &GraphQLVariable{
+			Name: nameToken.Text,
+		}
+		typeStart := parser.pos
+		if err = parser.parseType(); nil != err {
+			return nil, err
+		}
+		v.Type = joinGraphQLTokens(parser.tokens[typeStart:parser.pos])
+		if parser.skipPunctuator("=") {
+			valueStart := parser.pos
+			if err = parser.parseValue(true); nil != err {
+				return nil, err
+			}
+			v.DefaultValue = joinGraphQLTokens(parser.tokens[valueStart:parser.pos])
+		}
+		if err = parser.parseDirectives(true); nil != err {
+			return nil, err
+		}
+		variables = append(variables, v)
+	}
+	if len(variables) == 0 {
+		return nil, parser.errorAt(&parser.tokens[parser.pos-1], "empty variable definitions")
+	}
+	return
+}
+
+func (parser *graphQLParser) parseType() (err error) {
+	if parser.skipPunctuator("[") {
+		if err = parser.parseType(); nil != err {
+			return
+		}
+		if err = parser.expectPunctuator("]"); nil != err {
+			return
+		}
+	} else if _, err = parser.expectName(); nil != err {
+		return
+	}
+	parser.skipPunctuator("!")
+	return nil
+}
+
+func (parser *graphQLParser) parseValue(isConst bool) (err error) {
+	tok := parser.next()
+	switch tok.Type {
+	case graphQLTokenInt, graphQLTokenFloat, graphQLTokenString, graphQLTokenBlockString, graphQLTokenName:
+		return nil
+	case graphQLTokenPunctuator:
+		switch tok.Text {
+		case "$":
+			if isConst {
+				return parser.errorAt(tok, "variable is not allowed in constant value")
+			}
+			nameToken, err := parser.expectName()
+			if nil != err {
+				return err
+			}
+			parser.usedVariables = append(parser.usedVariables, nameToken)
+			return nil
+		case "[":
+			for !parser.skipPunctuator("]") {
+				if err = parser.parseValue(isConst); nil != err {
+					return
+				}
+			}
+			return nil
+		case "{":
+			for !parser.skipPunctuator("}") {
+				if _, err = parser.expectName(); nil != err {
+					return
+				}
+				if err = parser.expectPunctuator(":"); nil != err {
+					return
+				}
+				if err = parser.parseValue(isConst); nil != err {
+					return
+				}
+			}
+			return nil
+		}
+	}
+	return parser.errorAt(tok, "expecting value but having %s", tok.describe())
+}
+
+func (parser *graphQLParser) parseArguments(isConst bool) (err error) {
+	if !parser.skipPunctuator("(") {
+		return nil
+	}
+	if parser.peek().isPunctuator(")") {
+		return parser.errorAt(parser.peek(), "empty arguments")
+	}
+	for !parser.skipPunctuator(")") {
+		if _, err = parser.expectName(); nil != err {
+			return
+		}
+		if err = parser.expectPunctuator(":"); nil != err {
+			return
+		}
+		if err = parser.parseValue(isConst); nil != err {
+			return
+		}
+	}
+	return nil
+}
+
+func (parser *graphQLParser) parseDirectives(isConst bool) (err error) {
+	for parser.skipPunctuator("@") {
+		if _, err = parser.expectName(); nil != err {
+			return
+		}
+		if err = parser.parseArguments(isConst); nil != err {
+			return
+		}
+	}
+	return nil
+}
+
+func (parser *graphQLParser) parseSelectionSet() (err error) {
+	if err = parser.expectPunctuator("{"); nil != err {
+		return
+	}
+	if parser.peek().isPunctuator("}") {
+		return parser.errorAt(parser.peek(), "empty selection set")
+	}
+	for !parser.skipPunctuator("}") {
+		if parser.skipPunctuator("...") {
+			err = parser.parseFragmentSelection()
+		} else {
+			err = parser.parseField()
+		}
+		if nil != err {
+			return
+		}
+	}
+	return nil
+}
+
+func (parser *graphQLParser) parseField() (err error) {
+	if _, err = parser.expectName(); nil != err {
+		return
+	}
+	if parser.skipPunctuator(":") {
+		if _, err = parser.expectName(); nil != err {
+			return
+		}
+	}
+	if err = parser.parseArguments(false); nil != err {
+		return
+	}
+	if err = parser.parseDirectives(false); nil != err {
+		return
+	}
+	if parser.peek().isPunctuator("{") {
+		return parser.parseSelectionSet()
+	}
+	return nil
+}
+
+func (parser *graphQLParser) parseFragmentSelection() (err error) {
+	if tok := parser.peek(); (tok.Type == graphQLTokenName) && (tok.Text != "on") {
+		parser.pos++
+		return parser.parseDirectives(false)
+	}
+	if parser.peek().isName("on") {
+		parser.pos++
+		if _, err = parser.expectName(); nil != err {
+			return
+		}
+	}
+	if err = parser.parseDirectives(false); nil != err {
+		return
+	}
+	return parser.parseSelectionSet()
+}
+
+func (parser *graphQLParser) parseFragment() (err error) {
+	parser.pos++
+	nameToken, err := parser.expectName()
+	if nil != err {
+		return
+	}
+	if nameToken.Text == "on" {
+		return parser.errorAt(nameToken, "fragment name must not be \"on\"")
+	}
+	if _, ok := parser.fragments[nameToken.Text]; ok {
+		return parser.errorAt(nameToken, "duplicated fragment name: %s", nameToken.Text)
+	}
+	parser.fragments[nameToken.Text] = struct{}{}
+	if tok := parser.next(); !tok.isName("on") {
+		return parser.errorAt(tok, "expecting \"on\" but having %s", tok.describe())
+	}
+	if _, err = parser.expectName(); nil != err {
+		return
+	}
+	if err = parser.parseDirectives(false); nil != err {
+		return
+	}
+	return parser.parseSelectionSet()
+}
+
+// parseGraphQL lex and parse given GraphQL text. Tokens and operations
+// defined in text are returned.
+func parseGraphQL(text string) (tokens []graphQLToken, operations []*GraphQLOperation, err error) {
+	if tokens, err = lexGraphQL(text); nil != err {
+		return
+	}
+	parser := &graphQLParser{
+		text:      text,
+		tokens:    tokens,
+		fragments: make(map[string]struct{}),
+	}
+	if err = parser.parseDocument(); nil != err {
+		return
+	}
+	return tokens, parser.operations, nil
+}
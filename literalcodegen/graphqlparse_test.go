@@ -0,0 +1,102 @@
+package literalcodegen
+
+import (
+	"testing"
+)
+
+func TestParseGraphQL(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		signatures []string
+		variables  [][]string
+	}{
+		{
+			name:       "anonymous query",
+			text:       "{ user { id name } }",
+			signatures: []string{"query"},
+			variables:  [][]string{nil},
+		},
+		{
+			name:       "named query with variables",
+			text:       "query GetUser($id: ID!, $size: Int = 64) { user(id: $id) { avatar(size: $size) } }",
+			signatures: []string{"query GetUser($id: ID!, $size: Int = 64)"},
+			variables:  [][]string{{"id", "size"}},
+		},
+		{
+			name:       "list type and object default value",
+			text:       "query Q($ids: [ID!]!, $f: Filter = {a: 1, b: [\"x\"]}) { items(ids: $ids, filter: $f) { id } }",
+			signatures: []string{"query Q($ids: [ID!]!, $f: Filter = {a:1 b:[\"x\"]})"},
+			variables:  [][]string{{"ids", "f"}},
+		},
+		{
+			name: "quoted text and comments",
+			text: "# query Hidden { a }\nmutation SetName($n: String) {\n  # ) } \"\n  setName(name: $n, note: \"a } # b\", doc: \"\"\"c { d\"\"\") { ok }\n}",
+			signatures: []string{
+				"mutation SetName($n: String)",
+			},
+			variables: [][]string{{"n"}},
+		},
+		{
+			name: "operations and fragments",
+			text: "query A { ...F } subscription B { event { ... on X { id } } } fragment F on User { id }",
+			signatures: []string{
+				"query A",
+				"subscription B",
+			},
+			variables: [][]string{nil, nil},
+		},
+	}
+	for _, tt := range tests {
+		_, operations, err := parseGraphQL(tt.text)
+		if nil != err {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if len(operations) != len(tt.signatures) {
+			t.Errorf("%s: expecting %d operations but having %d", tt.name, len(tt.signatures), len(operations))
+			continue
+		}
+		for idx, op := range operations {
+			if signature := op.Signature(); signature != tt.signatures[idx] {
+				t.Errorf("%s: operation %d: expecting signature %q but having %q", tt.name, idx, tt.signatures[idx], signature)
+			}
+			names := op.VariableNames()
+			if len(names) != len(tt.variables[idx]) {
+				t.Errorf("%s: operation %d: expecting variables %v but having %v", tt.name, idx, tt.variables[idx], names)
+				continue
+			}
+			for nameIdx, name := range names {
+				if name != tt.variables[idx][nameIdx] {
+					t.Errorf("%s: operation %d: expecting variables %v but having %v", tt.name, idx, tt.variables[idx], names)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestParseGraphQLError(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"empty", ""},
+		{"comments only", "# query A { a }\n"},
+		{"unterminated string", "{ a(x: \"abc) }"},
+		{"unterminated selection set", "{ a { b }"},
+		{"empty selection set", "{ }"},
+		{"empty variable definitions", "query A() { a }"},
+		{"undefined variable", "query A($x: Int) { a(x: $y) }"},
+		{"duplicated variable", "query A($x: Int, $x: Int) { a(x: $x) }"},
+		{"variable in default value", "query A($x: Int = $y) { a(x: $x) }"},
+		{"duplicated operation name", "query A { a } query A { b }"},
+		{"anonymous operation with others", "{ a } query B { b }"},
+		{"duplicated fragment name", "fragment F on T { a } fragment F on T { b } { ...F }"},
+	}
+	for _, tt := range tests {
+		if _, _, err := parseGraphQL(tt.text); nil == err {
+			t.Errorf("%s: expecting error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package literalcodegen
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	RegisterLanguageFilter("html", LanguageFilterFunc(htmlContentFilter))
+}
+
+// htmlRawTextElements are elements which content is kept as-is.
+var htmlRawTextElements = []string{"pre", "textarea", "script", "style"}
+
+func newHTMLContentError(text string, pos int, message string) error {
+	return &ContentPositionError{
+		LineIndex: strings.Count(text[:pos], "\n"),
+		Column:    pos - strings.LastIndexByte(text[:pos], '\n'),
+		Err:       fmt.Errorf("%s", message),
+	}
+}
+
+func isHTMLTagStart(text string, pos int) bool {
+	if pos+1 >= len(text) {
+		return false
+	}
+	ch := text[pos+1]
+	return ((ch >= 'a') && (ch <= 'z')) || ((ch >= 'A') && (ch <= 'Z')) || (ch == '/') || (ch == '!') || (ch == '?')
+}
+
+// scanHTMLTag return end position of tag starts at given position.
+func scanHTMLTag(text string, start int) int {
+	for idx := start + 1; idx < len(text); idx++ {
+		switch text[idx] {
+		case '"', '\'':
+			quoteEnd := strings.IndexByte(text[idx+1:], text[idx])
+			if quoteEnd < 0 {
+				return -1
+			}
+			idx += quoteEnd + 1
+		case '>':
+			return idx + 1
+		}
+	}
+	return -1
+}
+
+func htmlTagName(tagText string) string {
+	name := strings.TrimPrefix(tagText[1:], "/")
+	if idx := strings.IndexAny(name, " \t\r\n\f/>"); idx >= 0 {
+		name = name[:idx]
+	}
+	return strings.ToLower(name)
+}
+
+func htmlRawTextElementOf(tagText string) string {
+	if strings.HasPrefix(tagText, "</") || strings.HasSuffix(tagText, "/>") {
+		return ""
+	}
+	name := htmlTagName(tagText)
+	for _, n := range htmlRawTextElements {
+		if n == name {
+			return n
+		}
+	}
+	return ""
+}
+
+// lexHTML split text into text (word), tag (punctuation), comment and raw
+// text (quoted) tokens.
+func lexHTML(text string) (tokens []minifyToken, err error) {
+	pos := 0
+	for pos < len(text) {
+		start := pos
+		var tokenType minifyTokenType
+		switch {
+		case strings.HasPrefix(text[pos:], "<!--"):
+			idx := strings.Index(text[pos+4:], "-->")
+			if idx < 0 {
+				return nil, newHTMLContentError(text, start, "unterminated HTML comment")
+			}
+			pos = pos + 4 + idx + 3
+			tokenType = minifyTokenComment
+		case (text[pos] == '<') && isHTMLTagStart(text, pos):
+			if pos = scanHTMLTag(text, pos); pos < 0 {
+				return nil, newHTMLContentError(text, start, "unterminated HTML tag")
+			}
+			tokenType = minifyTokenPunctuation
+		default:
+			pos++
+			for (pos < len(text)) && !((text[pos] == '<') && isHTMLTagStart(text, pos)) {
+				pos++
+			}
+			tokenType = minifyTokenWord
+		}
+		tokens = append(tokens, minifyToken{
+			Type: tokenType,
+			Text: text[start:pos],
+		})
+		if tokenType != minifyTokenPunctuation {
+			continue
+		}
+		if rawElement := htmlRawTextElementOf(text[start:pos]); rawElement != "" {
+			idx := strings.Index(strings.ToLower(text[pos:]), "</"+rawElement)
+			if idx < 0 {
+				return nil, newHTMLContentError(text, start, "unterminated HTML element: "+rawElement)
+			}
+			if idx > 0 {
+				tokens = append(tokens, minifyToken{
+					Type: minifyTokenQuoted,
+					Text: text[pos : pos+idx],
+				})
+			}
+			pos += idx
+		}
+	}
+	return
+}
+
+func isKeptHTMLComment(tok *minifyToken) bool {
+	return strings.HasPrefix(tok.Text, "<!--[if") || strings.HasPrefix(tok.Text, "<!--<![endif")
+}
+
+// collapseHTMLSpaces collapse white space runs into one space, or a new line
+// if the run contains new line.
+func collapseHTMLSpaces(text string) string {
+	var b strings.Builder
+	pos := 0
+	for pos < len(text) {
+		if !isMinifySpace(text[pos]) {
+			b.WriteByte(text[pos])
+			pos++
+			continue
+		}
+		end := scanMinifySpace(text, pos)
+		if strings.IndexByte(text[pos:end], '\n') >= 0 {
+			b.WriteByte('\n')
+		} else {
+			b.WriteByte(' ')
+		}
+		pos = end
+	}
+	return b.String()
+}
+
+// minifyHTMLTag collapse white spaces outside of attribute values in tag.
+func minifyHTMLTag(tagText, level string) string {
+	var b strings.Builder
+	pos := 0
+	for pos < len(tagText) {
+		ch := tagText[pos]
+		switch {
+		case (ch == '"') || (ch == '\''):
+			end := pos + 1 + strings.IndexByte(tagText[pos+1:], ch) + 1
+			b.WriteString(tagText[pos:end])
+			pos = end
+		case isMinifySpace(ch):
+			end := scanMinifySpace(tagText, pos)
+			next := tagText[end]
+			prev := tagText[pos-1]
+			if !((next == '>') || ((next == '/') && (end+1 < len(tagText)) && (tagText[end+1] == '>'))) &&
+				!((level == minifyAggressive) && ((next == '=') || (prev == '='))) {
+				b.WriteByte(' ')
+			}
+			pos = end
+		default:
+			b.WriteByte(ch)
+			pos++
+		}
+	}
+	return b.String()
+}
+
+// htmlTagAttribute return value of attribute with given (lower case) name in
+// tag. Attribute names are matched as a whole so `data-type` does not match
+// `type`.
+func htmlTagAttribute(tagText, name string) (value string, found bool) {
+	text := strings.TrimSuffix(strings.TrimSuffix(tagText, ">"), "/")
+	pos := 1 + len(htmlTagName(tagText))
+	for pos < len(text) {
+		for (pos < len(text)) && (isMinifySpace(text[pos]) || (text[pos] == '/')) {
+			pos++
+		}
+		start := pos
+		for (pos < len(text)) && !isMinifySpace(text[pos]) && (text[pos] != '=') && (text[pos] != '/') {
+			pos++
+		}
+		attrName := strings.ToLower(text[start:pos])
+		for (pos < len(text)) && isMinifySpace(text[pos]) {
+			pos++
+		}
+		var attrValue string
+		if (pos < len(text)) && (text[pos] == '=') {
+			pos++
+			for (pos < len(text)) && isMinifySpace(text[pos]) {
+				pos++
+			}
+			start = pos
+			if (pos < len(text)) && ((text[pos] == '"') || (text[pos] == '\'')) {
+				if end := strings.IndexByte(text[pos+1:], text[pos]); end >= 0 {
+					pos = pos + 1 + end + 1
+					attrValue = text[start+1 : pos-1]
+				} else {
+					pos = len(text)
+					attrValue = text[start+1:]
+				}
+			} else {
+				for (pos < len(text)) && !isMinifySpace(text[pos]) {
+					pos++
+				}
+				attrValue = text[start:pos]
+			}
+		}
+		if attrName == name {
+			return attrValue, true
+		}
+	}
+	return "", false
+}
+
+// isJavaScriptTag check if script tag contains JavaScript code.
+func isJavaScriptTag(tagText string) bool {
+	scriptType, found := htmlTagAttribute(tagText, "type")
+	if !found {
+		return true
+	}
+	scriptType = strings.ToLower(strings.TrimSpace(scriptType))
+	return (scriptType == "") || (scriptType == "text/javascript") || (scriptType == "module") || (scriptType == "application/javascript")
+}
+
+func minifyHTMLRawText(rawText, tagText, level string) (result string, err error) {
+	if level != minifyAggressive {
+		return rawText, nil
+	}
+	switch htmlTagName(tagText) {
+	case "style":
+		return minifyCSS(rawText, level)
+	case "script":
+		if isJavaScriptTag(tagText) {
+			return minifyJS(rawText, level)
+		}
+	}
+	return rawText, nil
+}
+
+// dropHTMLComments remove comments (except conditional comments) and merge
+// text tokens around removed comments.
+func dropHTMLComments(tokens []minifyToken) (result []minifyToken) {
+	for _, tok := range tokens {
+		if (tok.Type == minifyTokenComment) && !isKeptHTMLComment(&tok) {
+			tok = minifyToken{
+				Type: minifyTokenWord,
+				Text: strings.Repeat("\n", strings.Count(tok.Text, "\n")),
+			}
+		}
+		if lastIndex := len(result) - 1; (tok.Type == minifyTokenWord) && (lastIndex >= 0) && (result[lastIndex].Type == minifyTokenWord) {
+			result[lastIndex].Text = result[lastIndex].Text + tok.Text
+			continue
+		}
+		result = append(result, tok)
+	}
+	return
+}
+
+func minifyHTML(text, level string) (result string, err error) {
+	tokens, err := lexHTML(text)
+	if nil != err {
+		return
+	}
+	tokens = dropHTMLComments(tokens)
+	var b strings.Builder
+	lineOffset := 0
+	for idx := range tokens {
+		tok := &tokens[idx]
+		switch tok.Type {
+		case minifyTokenComment:
+			b.WriteString(tok.Text)
+		case minifyTokenPunctuation:
+			b.WriteString(minifyHTMLTag(tok.Text, level))
+		case minifyTokenQuoted:
+			rawText, err := minifyHTMLRawText(tok.Text, tokens[idx-1].Text, level)
+			if nil != err {
+				if posErr, ok := err.(*ContentPositionError); ok {
+					posErr.LineIndex += lineOffset
+				}
+				return "", err
+			}
+			b.WriteString(rawText)
+		case minifyTokenWord:
+			if (level == minifyAggressive) && (strings.TrimSpace(tok.Text) == "") && tok.hasNewLine() {
+				break
+			}
+			b.WriteString(collapseHTMLSpaces(tok.Text))
+		}
+		lineOffset += strings.Count(tok.Text, "\n")
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+func htmlContentFilter(codeContent []string, filterArgs LanguageFilterArgs) (result []string, err error) {
+	text, newLineMode := joinContentLines(codeContent)
+	if text, err = minifyHTML(text, parseMinifyLevel(filterArgs)); nil != err {
+		return
+	}
+	return newLineMode.restore(splitLinesWithNewLine(text)), nil
+}
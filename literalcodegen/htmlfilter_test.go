@@ -0,0 +1,97 @@
+package literalcodegen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHTMLContentFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    []string
+		filterArgs []string
+		expect     string
+	}{
+		{
+			name:    "conservative",
+			content: []string{"<div  class=\"a  b\">", "  <!-- note -->", "  <p>Hello   <b>world</b></p>", "  <pre>  x\n  y</pre>", "</div>"},
+			expect:  "<div class=\"a  b\">\n<p>Hello <b>world</b></p>\n<pre>  x\n  y</pre>\n</div>",
+		},
+		{
+			name:       "aggressive",
+			content:    []string{"<div  class=\"a  b\">", "  <!-- note -->", "  <p>Hello   <b>world</b></p>", "  <pre>  x\n  y</pre>", "</div>"},
+			filterArgs: []string{"aggressive"},
+			expect:     "<div class=\"a  b\"><p>Hello <b>world</b></p><pre>  x\n  y</pre></div>",
+		},
+		{
+			name:    "conservative keeps script and style",
+			content: []string{"<script>", "  var a = 1 ;  // c", "</script>", "<style> a { color : red ; } </style>"},
+			expect:  "<script>\n  var a = 1 ;  // c\n</script>\n<style> a { color : red ; } </style>",
+		},
+		{
+			name:       "aggressive minifies script and style",
+			content:    []string{"<script type=\"text/javascript\">", "  var a = 1 ;  // c", "</script>", "<style> a { color : red ; } </style>"},
+			filterArgs: []string{"aggressive"},
+			expect:     "<script type=\"text/javascript\">var a=1;</script><style>a{color:red}</style>",
+		},
+		{
+			name:       "aggressive keeps non-JavaScript script",
+			content:    []string{"<script data-type=\"module\" type=\"text/template\">  <b> </b>  </script>"},
+			filterArgs: []string{"aggressive"},
+			expect:     "<script data-type=\"module\" type=\"text/template\">  <b> </b>  </script>",
+		},
+		{
+			name:       "conditional comment",
+			content:    []string{"<!--[if IE]><p>x</p><![endif]-->", "<p>a</p>"},
+			filterArgs: []string{"aggressive"},
+			expect:     "<!--[if IE]><p>x</p><![endif]--><p>a</p>",
+		},
+	}
+	for _, tt := range tests {
+		result, err := htmlContentFilter(tt.content, LanguageFilterArgs(tt.filterArgs))
+		if nil != err {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if text := strings.Join(result, ""); text != tt.expect {
+			t.Errorf("%s: expecting %q but having %q", tt.name, tt.expect, text)
+		}
+	}
+}
+
+func TestIsJavaScriptTag(t *testing.T) {
+	tests := []struct {
+		tagText string
+		expect  bool
+	}{
+		{"<script>", true},
+		{"<script type=\"text/javascript\">", true},
+		{"<script type='module'>", true},
+		{"<script type=\"\">", true},
+		{"<script type=\"text/template\">", false},
+		{"<script data-type=\"text/javascript\" type=\"text/template\">", false},
+		{"<script data-type=\"text/template\">", true},
+		{"<script TYPE=application/json>", false},
+	}
+	for _, tt := range tests {
+		if result := isJavaScriptTag(tt.tagText); result != tt.expect {
+			t.Errorf("%s: expecting %v but having %v", tt.tagText, tt.expect, result)
+		}
+	}
+}
+
+func TestHTMLContentFilterError(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []string
+	}{
+		{"unterminated tag", []string{"<div"}},
+		{"unterminated comment", []string{"<!-- x"}},
+		{"unterminated script", []string{"<script>x"}},
+	}
+	for _, tt := range tests {
+		if _, err := htmlContentFilter(tt.content, nil); nil == err {
+			t.Errorf("%s: expecting error", tt.name)
+		}
+	}
+}
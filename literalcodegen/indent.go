@@ -0,0 +1,154 @@
+package literalcodegen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultTabWidth is the tab width of `expand-tabs` option when width is not given.
+const DefaultTabWidth = 4
+
+// Line endings of generated literals.
+const (
+	LineEndingLF   = "lf"
+	LineEndingCRLF = "crlf"
+)
+
+func isIndentChar(ch byte) bool {
+	return (ch == ' ') || (ch == '\t')
+}
+
+func leadingIndent(line string) string {
+	idx := 0
+	for (idx < len(line)) && isIndentChar(line[idx]) {
+		idx++
+	}
+	return line[:idx]
+}
+
+// expandLeadingTabs replace tabs in indent of given line with spaces up to
+// next tab stop.
+func expandLeadingTabs(line string, tabWidth int) string {
+	indent := leadingIndent(line)
+	if strings.IndexByte(indent, '\t') < 0 {
+		return line
+	}
+	var b strings.Builder
+	column := 0
+	for idx := 0; idx < len(indent); idx++ {
+		if indent[idx] == '\t' {
+			spaces := tabWidth - (column % tabWidth)
+			b.WriteString(strings.Repeat(" ", spaces))
+			column += spaces
+		} else {
+			b.WriteByte(' ')
+			column++
+		}
+	}
+	return b.String() + line[len(indent):]
+}
+
+// commonIndent return the longest indent shared by all non-blank lines.
+func commonIndent(lines []string) (result string) {
+	first := true
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := leadingIndent(line)
+		if first {
+			result = indent
+			first = false
+			continue
+		}
+		idx := 0
+		for (idx < len(result)) && (idx < len(indent)) && (result[idx] == indent[idx]) {
+			idx++
+		}
+		result = result[:idx]
+	}
+	return
+}
+
+// reindentLines expand tabs in indent and remove common indent of given
+// lines according to options of entry.
+func (entry *LiteralEntry) reindentLines(lines []string) []string {
+	if entry.TabWidth > 0 {
+		for idx, line := range lines {
+			lines[idx] = expandLeadingTabs(line, entry.TabWidth)
+		}
+	}
+	if entry.Dedent {
+		if indent := commonIndent(lines); indent != "" {
+			for idx, line := range lines {
+				lines[idx] = strings.TrimPrefix(line, indent)
+			}
+		}
+	}
+	return lines
+}
+
+func (entry *LiteralEntry) setTabWidthText(v string) (err error) {
+	tabWidth, err := strconv.Atoi(strings.TrimSpace(v))
+	if (nil != err) || (tabWidth <= 0) {
+		return fmt.Errorf("tab width must be positive integer: %q", v)
+	}
+	entry.TabWidth = tabWidth
+	return nil
+}
+
+func (entry *LiteralEntry) setLineEndingText(v string) (err error) {
+	switch lineEnding := strings.ToLower(strings.TrimSpace(v)); lineEnding {
+	case LineEndingLF, LineEndingCRLF:
+		entry.LineEnding = lineEnding
+		return nil
+	}
+	return fmt.Errorf("line ending must be one of %s or %s: %q", LineEndingLF, LineEndingCRLF, v)
+}
+
+// checkLineEndingOptions reject `line-ending` option on entries where it has
+// no effect: content without new line characters and translation modes which
+// do not generate literal text.
+func checkLineEndingOptions(code *LiteralCode) (err error) {
+	for _, entry := range code.LiteralConstants {
+		if entry.LineEnding == "" {
+			continue
+		}
+		switch {
+		case (entry.TranslationMode == TranslateAsNoop) || (entry.TranslationMode == TranslateAsExplicitNoop):
+			continue
+		case (entry.TranslationMode == TranslateAsRegexp) || (entry.TranslationMode == TranslateAsTemplate):
+			return fmt.Errorf("%v: line-ending option is not supported by regexp and template entries (entry: %v)", entry.TitleLocation, entry.TitleText)
+		case !entry.PreserveNewLine && !entry.TailNewLine:
+			return fmt.Errorf("%v: line-ending option requires preserve-new-line or tail-new-line option (entry: %v)", entry.TitleLocation, entry.TitleText)
+		}
+	}
+	return nil
+}
+
+// applyLineEnding convert new line characters in given literal text into
+// line ending of entry.
+func (entry *LiteralEntry) applyLineEnding(text string) string {
+	if entry.LineEnding == "" {
+		return text
+	}
+	text = strings.Replace(text, "\r\n", "\n", -1)
+	if entry.LineEnding == LineEndingCRLF {
+		text = strings.Replace(text, "\n", "\r\n", -1)
+	}
+	return text
+}
+
+// applyLineEndingToContent return copy of given content with line ending of
+// entry applied.
+func (entry *LiteralEntry) applyLineEndingToContent(content []string) []string {
+	if entry.LineEnding == "" {
+		return content
+	}
+	result := make([]string, len(content))
+	for idx, line := range content {
+		result[idx] = entry.applyLineEnding(line)
+	}
+	return result
+}
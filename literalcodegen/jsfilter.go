@@ -0,0 +1,257 @@
+package literalcodegen
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	RegisterLanguageFilter("js", LanguageFilterFunc(jsContentFilter))
+}
+
+// jsRegexpPrecedingKeywords are keywords which can be followed by regular
+// expression literal.
+var jsRegexpPrecedingKeywords = map[string]struct{}{
+	"return":     {},
+	"typeof":     {},
+	"instanceof": {},
+	"in":         {},
+	"of":         {},
+	"new":        {},
+	"delete":     {},
+	"void":       {},
+	"throw":      {},
+	"case":       {},
+	"do":         {},
+	"else":       {},
+	"yield":      {},
+	"await":      {},
+}
+
+func isJSWordChar(ch byte) bool {
+	return ((ch >= 'a') && (ch <= 'z')) || ((ch >= 'A') && (ch <= 'Z')) || ((ch >= '0') && (ch <= '9')) || (ch == '_') || (ch == '$') || (ch == '\\') || (ch >= 0x80)
+}
+
+func newJSContentError(text string, pos int, message string) error {
+	return &ContentPositionError{
+		LineIndex: strings.Count(text[:pos], "\n"),
+		Column:    pos - strings.LastIndexByte(text[:pos], '\n'),
+		Err:       fmt.Errorf("%s", message),
+	}
+}
+
+// scanJSRegexp return end position of regular expression literal (including
+// flags) starts at given position.
+func scanJSRegexp(text string, start int) int {
+	inClass := false
+	for idx := start + 1; idx < len(text); idx++ {
+		switch text[idx] {
+		case '\\':
+			idx++
+		case '\n':
+			return -1
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '/':
+			if !inClass {
+				idx++
+				for (idx < len(text)) && isJSWordChar(text[idx]) {
+					idx++
+				}
+				return idx
+			}
+		}
+	}
+	return -1
+}
+
+// scanJSTemplate return end position of template literal starts at given
+// position. Substitutions are skipped with nested string and template literals.
+func scanJSTemplate(text string, start int) int {
+	for idx := start + 1; idx < len(text); idx++ {
+		switch text[idx] {
+		case '\\':
+			idx++
+		case '`':
+			return idx + 1
+		case '$':
+			if (idx+1 < len(text)) && (text[idx+1] == '{') {
+				if idx = scanJSSubstitution(text, idx+2); idx < 0 {
+					return -1
+				}
+				idx--
+			}
+		}
+	}
+	return -1
+}
+
+// scanJSSubstitution return position right after the closing brace of
+// template substitution which body starts at given position.
+func scanJSSubstitution(text string, start int) int {
+	depth := 1
+	for idx := start; idx < len(text); idx++ {
+		switch ch := text[idx]; ch {
+		case '{':
+			depth++
+		case '}':
+			if depth--; depth == 0 {
+				return idx + 1
+			}
+		case '"', '\'':
+			if idx = scanMinifyQuoted(text, idx); idx < 0 {
+				return -1
+			}
+			idx--
+		case '`':
+			if idx = scanJSTemplate(text, idx); idx < 0 {
+				return -1
+			}
+			idx--
+		}
+	}
+	return -1
+}
+
+func isJSRegexpAllowedAfter(tokens []minifyToken, prevIndex int) bool {
+	if prevIndex < 0 {
+		return true
+	}
+	prev := &tokens[prevIndex]
+	switch prev.Type {
+	case minifyTokenWord:
+		_, ok := jsRegexpPrecedingKeywords[prev.Text]
+		return ok
+	case minifyTokenPunctuation:
+		return !prev.isPunctuationOf(")]}")
+	}
+	return false
+}
+
+func lexJS(text string) (tokens []minifyToken, err error) {
+	prevIndex := -1
+	pos := 0
+	for pos < len(text) {
+		start := pos
+		ch := text[pos]
+		var tokenType minifyTokenType
+		switch {
+		case isMinifySpace(ch):
+			pos = scanMinifySpace(text, pos)
+			tokenType = minifyTokenWhitespace
+		case strings.HasPrefix(text[pos:], "//"):
+			if idx := strings.IndexByte(text[pos:], '\n'); idx < 0 {
+				pos = len(text)
+			} else {
+				pos += idx
+			}
+			tokenType = minifyTokenComment
+		case strings.HasPrefix(text[pos:], "/*"):
+			idx := strings.Index(text[pos+2:], "*/")
+			if idx < 0 {
+				return nil, newJSContentError(text, start, "unterminated JavaScript comment")
+			}
+			pos = pos + 2 + idx + 2
+			tokenType = minifyTokenComment
+		case (ch == '"') || (ch == '\''):
+			if pos = scanMinifyQuoted(text, pos); pos < 0 {
+				return nil, newJSContentError(text, start, "unterminated JavaScript string")
+			}
+			tokenType = minifyTokenQuoted
+		case ch == '`':
+			if pos = scanJSTemplate(text, pos); pos < 0 {
+				return nil, newJSContentError(text, start, "unterminated JavaScript template literal")
+			}
+			tokenType = minifyTokenQuoted
+		case (ch == '/') && isJSRegexpAllowedAfter(tokens, prevIndex):
+			if pos = scanJSRegexp(text, pos); pos < 0 {
+				return nil, newJSContentError(text, start, "unterminated JavaScript regular expression")
+			}
+			tokenType = minifyTokenQuoted
+		case isJSWordChar(ch):
+			for (pos < len(text)) && (isJSWordChar(text[pos]) || ((text[pos] == '.') && isJSNumberStart(text[start]))) {
+				pos++
+			}
+			tokenType = minifyTokenWord
+		default:
+			pos++
+			tokenType = minifyTokenPunctuation
+		}
+		tokens = append(tokens, minifyToken{
+			Type: tokenType,
+			Text: text[start:pos],
+		})
+		if (tokenType != minifyTokenWhitespace) && (tokenType != minifyTokenComment) {
+			prevIndex = len(tokens) - 1
+		}
+	}
+	return
+}
+
+func isJSNumberStart(ch byte) bool {
+	return (ch >= '0') && (ch <= '9')
+}
+
+// jsNeedSpace check if tokens will merge into different tokens when white
+// space between them is removed.
+func jsNeedSpace(prev, next *minifyToken) bool {
+	prevCh := prev.Text[len(prev.Text)-1]
+	nextCh := next.Text[0]
+	if isJSWordChar(prevCh) && (isJSWordChar(nextCh) || ((nextCh == '.') && (prev.Type == minifyTokenWord) && isJSNumberStart(prev.Text[0]))) {
+		return true
+	}
+	switch string([]byte{prevCh, nextCh}) {
+	case "++", "--", "//", "/*", "<!", "->":
+		return true
+	}
+	return false
+}
+
+// jsLineBreakRemovable check if line break between tokens can be removed
+// without triggering (or suppressing) automatic semicolon insertion.
+func jsLineBreakRemovable(prev, next *minifyToken) bool {
+	if prev.isPunctuationOf("{([,;=:?&|!~*%<>^") {
+		return true
+	}
+	return next.isPunctuationOf("})],;:?&|=*%<>^")
+}
+
+func isKeptJSComment(tok *minifyToken) bool {
+	return strings.HasPrefix(tok.Text, "/*!")
+}
+
+func minifyJS(text, level string) (result string, err error) {
+	tokens, err := lexJS(text)
+	if nil != err {
+		return
+	}
+	tokens = mergeMinifySpaces(tokens, isKeptJSComment)
+	var b strings.Builder
+	for idx := range tokens {
+		tok := &tokens[idx]
+		if tok.Type != minifyTokenWhitespace {
+			b.WriteString(tok.Text)
+			continue
+		}
+		prev, next := significantMinifyTokenAround(tokens, idx)
+		if (nil == prev) || (nil == next) {
+			continue
+		}
+		if tok.hasNewLine() && ((level != minifyAggressive) || !jsLineBreakRemovable(prev, next)) {
+			b.WriteString("\n")
+		} else if jsNeedSpace(prev, next) {
+			b.WriteString(" ")
+		}
+	}
+	return b.String(), nil
+}
+
+func jsContentFilter(codeContent []string, filterArgs LanguageFilterArgs) (result []string, err error) {
+	text, newLineMode := joinContentLines(codeContent)
+	if text, err = minifyJS(text, parseMinifyLevel(filterArgs)); nil != err {
+		return
+	}
+	return newLineMode.restore(splitLinesWithNewLine(text)), nil
+}
@@ -0,0 +1,65 @@
+package literalcodegen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJSContentFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    []string
+		filterArgs []string
+		expect     string
+	}{
+		{
+			name:    "conservative",
+			content: []string{"// c", "var a = 1 ;", "var s = 'x  y' + `t ${ a }  `;", "/* b */ var r = /a  b/g ;", "a = b", "++c"},
+			expect:  "var a=1;\nvar s='x  y'+`t ${ a }  `;\nvar r=/a  b/g;\na=b\n++c",
+		},
+		{
+			name:       "aggressive",
+			content:    []string{"// c", "var a = 1 ;", "var s = 'x  y' + `t ${ a }  `;", "/* b */ var r = /a  b/g ;", "a = b", "++c"},
+			filterArgs: []string{"aggressive"},
+			expect:     "var a=1;var s='x  y'+`t ${ a }  `;var r=/a  b/g;a=b\n++c",
+		},
+		{
+			name:       "separated operators and kept comment",
+			content:    []string{"function f ( x ) {", "  return x + + 1", "}", "/*! license */"},
+			filterArgs: []string{"aggressive"},
+			expect:     "function f(x){return x+ +1}/*! license */",
+		},
+		{
+			name:       "line break before parenthesis",
+			content:    []string{"let x = a", "(b)"},
+			filterArgs: []string{"aggressive"},
+			expect:     "let x=a\n(b)",
+		},
+	}
+	for _, tt := range tests {
+		result, err := jsContentFilter(tt.content, LanguageFilterArgs(tt.filterArgs))
+		if nil != err {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if text := strings.Join(result, ""); text != tt.expect {
+			t.Errorf("%s: expecting %q but having %q", tt.name, tt.expect, text)
+		}
+	}
+}
+
+func TestJSContentFilterError(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []string
+	}{
+		{"unterminated string", []string{"var a = 'abc"}},
+		{"unterminated comment", []string{"/* x"}},
+		{"unterminated template literal", []string{"var a = `a ${"}},
+	}
+	for _, tt := range tests {
+		if _, err := jsContentFilter(tt.content, nil); nil == err {
+			t.Errorf("%s: expecting error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package literalcodegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+func init() {
+	RegisterLanguageFilter("json", LanguageFilterFunc(jsonContentFilter))
+}
+
+const (
+	jsonOutputKeep   = "keep"
+	jsonOutputMinify = "minify"
+	jsonOutputIndent = "indent"
+)
+
+type jsonFilterOptions struct {
+	outputMode string
+	indent     string
+	sortKeys   bool
+}
+
+func parseJSONFilterArgs(filterArgs LanguageFilterArgs) (opts *jsonFilterOptions) {
+	return &jsonFilterOptions{
+		outputMode: filterArgs.Choice(jsonOutputKeep, jsonOutputMinify, jsonOutputIndent),
+		indent:     strings.Replace(filterArgs.ValueOr("indent-text", "  "), "\\t", "\t", -1),
+		sortKeys:   filterArgs.Has("sort-keys"),
+	}
+}
+
+// jsonSyntaxErrorPosition return position of the character at given offset.
+// Offset reported by encoding/json is the count of bytes read including the
+// problematic character.
+func jsonSyntaxErrorPosition(text string, offset int64) (lineIndex, column int) {
+	if offset > int64(len(text)) {
+		offset = int64(len(text))
+	}
+	if offset > 0 {
+		offset--
+	}
+	prefix := text[:offset]
+	lineIndex = strings.Count(prefix, "\n")
+	column = len(prefix) - strings.LastIndexByte(prefix, '\n')
+	return
+}
+
+func newJSONContentError(text string, err error) error {
+	switch e := err.(type) {
+	case *json.SyntaxError:
+		lineIndex, column := jsonSyntaxErrorPosition(text, e.Offset)
+		return &ContentPositionError{
+			LineIndex: lineIndex,
+			Column:    column,
+			Err:       e,
+		}
+	case *json.UnmarshalTypeError:
+		lineIndex, column := jsonSyntaxErrorPosition(text, e.Offset)
+		return &ContentPositionError{
+			LineIndex: lineIndex,
+			Column:    column,
+			Err:       e,
+		}
+	}
+	return err
+}
+
+func sortJSONKeys(text string) (result []byte, err error) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	var value interface{}
+	if err = decoder.Decode(&value); nil != err {
+		return
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err = encoder.Encode(value); nil != err {
+		return
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+func jsonContentFilter(codeContent []string, filterArgs LanguageFilterArgs) (result []string, err error) {
+	opts := parseJSONFilterArgs(filterArgs)
+	text, newLineMode := joinContentLines(codeContent)
+	if !json.Valid([]byte(text)) {
+		var value interface{}
+		if err = json.Unmarshal([]byte(text), &value); nil == err {
+			err = fmt.Errorf("invalid JSON")
+		}
+		return nil, newJSONContentError(text, err)
+	}
+	if (opts.outputMode == jsonOutputKeep) && !opts.sortKeys {
+		return codeContent, nil
+	}
+	data := []byte(text)
+	if opts.sortKeys {
+		if data, err = sortJSONKeys(text); nil != err {
+			return
+		}
+	}
+	// Layout is lost once keys are sorted, so keep mode falls back to minify.
+	var buf bytes.Buffer
+	switch opts.outputMode {
+	case jsonOutputIndent:
+		err = json.Indent(&buf, data, "", opts.indent)
+	default:
+		err = json.Compact(&buf, data)
+	}
+	if nil != err {
+		return nil, newJSONContentError(text, err)
+	}
+	if opts.outputMode == jsonOutputIndent {
+		return newLineMode.restore(splitLinesWithNewLine(buf.String())), nil
+	}
+	return newLineMode.restore([]string{buf.String()}), nil
+}
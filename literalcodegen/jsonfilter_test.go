@@ -0,0 +1,94 @@
+package literalcodegen
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestJSONContentFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    []string
+		filterArgs []string
+		expect     string
+	}{
+		{
+			name:    "keep",
+			content: []string{"{", "  \"b\": [1, 2],", "  \"a\": \"x y\"", "}"},
+			expect:  "{  \"b\": [1, 2],  \"a\": \"x y\"}",
+		},
+		{
+			name:    "keep with new line on each line",
+			content: []string{"{\n", "  \"a\": 1\n", "}\n"},
+			expect:  "{\n  \"a\": 1\n}\n",
+		},
+		{
+			name:       "minify",
+			content:    []string{"{", "  \"b\": [1, 2],", "  \"a\": \"x y\"", "}"},
+			filterArgs: []string{"minify"},
+			expect:     "{\"b\":[1,2],\"a\":\"x y\"}",
+		},
+		{
+			name:       "indent",
+			content:    []string{"{\"a\": [1, 2]}"},
+			filterArgs: []string{"indent"},
+			expect:     "{\n  \"a\": [\n    1,\n    2\n  ]\n}",
+		},
+		{
+			name:       "indent with tab",
+			content:    []string{"{\"a\": 1}"},
+			filterArgs: []string{"indent", "indent-text=\\t"},
+			expect:     "{\n\t\"a\": 1\n}",
+		},
+		{
+			name:       "sort keys",
+			content:    []string{"{\"b\": 1, \"a\": {\"d\": 1.50, \"c\": \"<&>\"}}"},
+			filterArgs: []string{"sort-keys"},
+			expect:     "{\"a\":{\"c\":\"<&>\",\"d\":1.50},\"b\":1}",
+		},
+	}
+	for _, tt := range tests {
+		result, err := jsonContentFilter(tt.content, LanguageFilterArgs(tt.filterArgs))
+		if nil != err {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		text := strings.Join(result, "")
+		if text != tt.expect {
+			t.Errorf("%s: expecting %q but having %q", tt.name, tt.expect, text)
+			continue
+		}
+		if !json.Valid([]byte(text)) {
+			t.Errorf("%s: output is not valid JSON: %q", tt.name, text)
+		}
+	}
+}
+
+func TestJSONContentFilterError(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   []string
+		lineIndex int
+		column    int
+	}{
+		{"tailing comma", []string{"{", "  \"a\": 1,", "}"}, 2, 1},
+		{"missing value", []string{"{\"a\": }"}, 0, 7},
+		{"empty", []string{}, 0, 1},
+	}
+	for _, tt := range tests {
+		_, err := jsonContentFilter(tt.content, nil)
+		if nil == err {
+			t.Errorf("%s: expecting error", tt.name)
+			continue
+		}
+		posErr, ok := err.(*ContentPositionError)
+		if !ok {
+			t.Errorf("%s: expecting position error but having %T: %v", tt.name, err, err)
+			continue
+		}
+		if (posErr.LineIndex != tt.lineIndex) || (posErr.Column != tt.column) {
+			t.Errorf("%s: expecting error at %d:%d but having %d:%d: %v", tt.name, tt.lineIndex, tt.column, posErr.LineIndex, posErr.Column, err)
+		}
+	}
+}
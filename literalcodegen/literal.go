@@ -1,6 +1,7 @@
 package literalcodegen
 
 import (
+	"fmt"
 	"log"
 	"strings"
 	"unicode"
@@ -21,6 +22,12 @@ const (
 
 	// TranslateAsBuilder set translation mode to builder function
 	TranslateAsBuilder
+
+	// TranslateAsRegexp set translation mode to precompiled regular expression
+	TranslateAsRegexp
+
+	// TranslateAsTemplate set translation mode to parsed template
+	TranslateAsTemplate
 )
 
 // SubWorkType represent type of sub-works.
@@ -49,11 +56,37 @@ type LiteralEntry struct {
 	KeepEmptyLine         bool
 	TailNewLine           bool
 	DisableLanguageFilter bool
+	FilterCommand         string
+	Dedent                bool
+	TabWidth              int
+	LineEnding            string
+
+	Locale           string
+	LocaleVariants   []*LiteralEntry
+	LocaleVariantOf  *LiteralEntry
+	LocaleLookupName string
+	LocaleLookupKind string
+
+	SQLDialect             string
+	SQLDialects            []string
+	SQLDialectVariants     []*LiteralEntry
+	SQLDialectVariantOf    *LiteralEntry
+	SQLDialectSelectorName string
+
+	TimeLayout    string
+	StringerTypes []string
+
+	TemplateFuncs      []*TemplateFunc
+	TemplateLeftDelim  string
+	TemplateRightDelim string
 
 	Content            []string
 	LanguageType       string
 	LanguageFilterArgs []string
 
+	TitleLocation   SourceLocation
+	ContentLocation SourceLocation
+
 	BuilderPrepare *LiteralEntry
 
 	ParentEntry  *LiteralEntry
@@ -61,7 +94,19 @@ type LiteralEntry struct {
 
 	ExternalFilterData interface{}
 
+	ReplaceRuleSetNames []string
+
 	replaceRules []*ReplaceRule
+
+	contentLocations []SourceLocation
+
+	// localeFromOption is set when locale is given by `locale` option
+	// instead of tag of code block.
+	localeFromOption bool
+
+	// sqlDialectFromOption is set when SQL dialect is given by `sql-dialect`
+	// option instead of tag of code block.
+	sqlDialectFromOption bool
 }
 
 // NewLiteralEntry create a new instance of LiteralEntry and set properties to default values
@@ -83,10 +128,18 @@ func (entry *LiteralEntry) GetBuilderPrepareNode() *LiteralEntry {
 
 // AppendContent add given content line by line and transform with specified configuration
 func (entry *LiteralEntry) AppendContent(content, langType string, langFilterArgs []string) {
+	entry.AppendContentAt(content, langType, langFilterArgs, SourceLocation{})
+}
+
+// AppendContentAt add given content which starts at given location of Markdown input.
+func (entry *LiteralEntry) AppendContentAt(content, langType string, langFilterArgs []string, location SourceLocation) {
+	if entry.ContentLocation.IsZero() {
+		entry.ContentLocation = location
+	}
 	if entry.KeepEmptyLine {
 		content = strings.TrimRightFunc(content, unicode.IsSpace)
 	}
-	lineBuffer := strings.Split(content, "\n")
+	lineBuffer := entry.reindentLines(strings.Split(content, "\n"))
 	lastLineIndex := len(lineBuffer) - 1
 	for idx, line := range lineBuffer {
 		if entry.TrimSpace {
@@ -100,6 +153,7 @@ func (entry *LiteralEntry) AppendContent(content, langType string, langFilterArg
 			continue
 		}
 		entry.Content = append(entry.Content, line)
+		entry.contentLocations = append(entry.contentLocations, location.offset(idx))
 	}
 	if entry.LanguageType == "" {
 		entry.LanguageType = langType
@@ -113,12 +167,79 @@ func (entry *LiteralEntry) appendReplaceRule(rule *ReplaceRule) {
 	entry.replaceRules = append(entry.replaceRules, rule)
 }
 
-// FilteredContent return content filtered with language filter
+// FilteredContent return content filtered with language filter and filter
+// command of entry.
 func (entry *LiteralEntry) FilteredContent() (content []string, err error) {
+	content = entry.Content
+	if !entry.DisableLanguageFilter {
+		if content, err = runLanaguageFilter(entry.LanguageType, entry.Content, entry.LanguageFilterArgs); nil != err {
+			return nil, entry.languageFilterError(err)
+		}
+	}
+	if entry.FilterCommand != "" {
+		return entry.filterWithCommand(content)
+	}
+	return content, nil
+}
+
+func (entry *LiteralEntry) languageFilterError(err error) error {
+	if posErr, ok := err.(*ContentPositionError); ok {
+		location := entry.ContentLineLocation(posErr.LineIndex)
+		if posErr.Column > 0 {
+			return fmt.Errorf("%v:%d: language filter (%s) failed on entry [%s]: %v", location, posErr.Column, entry.LanguageType, entry.TitleText, posErr.Err)
+		}
+		return fmt.Errorf("%v: language filter (%s) failed on entry [%s]: %v", location, entry.LanguageType, entry.TitleText, posErr.Err)
+	}
+	return fmt.Errorf("%v: language filter (%s) failed on entry [%s]: %v", entry.ContentLocation, entry.LanguageType, entry.TitleText, err)
+}
+
+// IsSplitContent checks if content of entry is split into pieces by language filter.
+func (entry *LiteralEntry) IsSplitContent() bool {
+	if entry.DisableLanguageFilter {
+		return false
+	}
+	return nil != lookupLanguageContentSplitter(entry.LanguageType, entry.LanguageFilterArgs)
+}
+
+// FilteredContentPieces return content pieces split by language filter.
+// Nil will be return if content is not split. An empty slice is returned if
+// content is split into no pieces (eg: content only contains comments).
+func (entry *LiteralEntry) FilteredContentPieces() (pieces [][]string, err error) {
 	if entry.DisableLanguageFilter {
-		return entry.Content, nil
+		return nil, nil
+	}
+	splitter := lookupLanguageContentSplitter(entry.LanguageType, entry.LanguageFilterArgs)
+	if nil == splitter {
+		return nil, nil
+	}
+	if pieces, err = splitter.SplitContent(entry.Content, LanguageFilterArgs(entry.LanguageFilterArgs)); nil != err {
+		return nil, entry.languageFilterError(err)
+	}
+	if nil == pieces {
+		pieces = [][]string{}
+	}
+	if entry.FilterCommand != "" {
+		for idx, piece := range pieces {
+			if pieces[idx], err = entry.filterWithCommand(piece); nil != err {
+				return nil, err
+			}
+		}
+	}
+	return pieces, nil
+}
+
+// ContentLineLocation return location of content line at given index.
+// Location of content will be return if location of line is not available.
+func (entry *LiteralEntry) ContentLineLocation(index int) SourceLocation {
+	if (index >= 0) && (index < len(entry.contentLocations)) && (len(entry.contentLocations) == len(entry.Content)) {
+		if loc := entry.contentLocations[index]; !loc.IsZero() {
+			return loc
+		}
 	}
-	return runLanaguageFilter(entry.LanguageType, entry.Content, entry.LanguageFilterArgs)
+	if !entry.ContentLocation.IsZero() {
+		return entry.ContentLocation
+	}
+	return entry.TitleLocation
 }
 
 // FilteredContentLine return first line from filtered content
@@ -139,15 +260,30 @@ func (entry *LiteralEntry) FilteredContentLine() (contentLine string, err error)
 }
 
 func (entry *LiteralEntry) attachToParent(parent *LiteralEntry) {
+	entry.copyOptionsFrom(parent)
+	entry.LevelDepth = parent.LevelDepth + 1
+	entry.ParentEntry = parent
+	parent.ChildEntries = append(parent.ChildEntries, entry)
+}
+
+// copyOptionsFrom copy options which are inherited by child entries.
+func (entry *LiteralEntry) copyOptionsFrom(parent *LiteralEntry) {
 	entry.TranslationMode = parent.TranslationMode
 	entry.TrimSpace = parent.TrimSpace
 	entry.PreserveNewLine = parent.PreserveNewLine
 	entry.KeepEmptyLine = parent.KeepEmptyLine
 	entry.TailNewLine = parent.TailNewLine
 	entry.DisableLanguageFilter = parent.DisableLanguageFilter
-	entry.LevelDepth = parent.LevelDepth + 1
-	entry.ParentEntry = parent
-	parent.ChildEntries = append(parent.ChildEntries, entry)
+	entry.FilterCommand = parent.FilterCommand
+	entry.Dedent = parent.Dedent
+	entry.TabWidth = parent.TabWidth
+	entry.LineEnding = parent.LineEnding
+	entry.TimeLayout = parent.TimeLayout
+	entry.StringerTypes = parent.StringerTypes
+}
+
+func (entry *LiteralEntry) appendReplaceRuleSetName(name string) {
+	entry.ReplaceRuleSetNames = append(entry.ReplaceRuleSetNames, name)
 }
 
 // PushDownReplaceRules pushes replacing rules to children nodes without replacing rules
@@ -169,6 +305,8 @@ func (entry *LiteralEntry) PushDownReplaceRules() {
 type LiteralCode struct {
 	HeadingCodes     []*LiteralEntry
 	LiteralConstants []*LiteralEntry
+	ReplaceRuleSets  []*LiteralEntry
+	Variables        map[string]string
 }
 
 // NewHeadingCode allocate and append one literal entry as heading code node
@@ -184,3 +322,56 @@ func (l *LiteralCode) NewLiteralConstant() (allocated *LiteralEntry) {
 	l.LiteralConstants = append(l.LiteralConstants, allocated)
 	return allocated
 }
+
+// NewReplaceRuleSet allocate and append one literal entry as named replace rule set node
+func (l *LiteralCode) NewReplaceRuleSet(name string) (allocated *LiteralEntry) {
+	allocated = NewLiteralEntry()
+	allocated.Name = name
+	allocated.TranslationMode = TranslateAsExplicitNoop
+	l.ReplaceRuleSets = append(l.ReplaceRuleSets, allocated)
+	return allocated
+}
+
+// FindReplaceRuleSet return replace rule set node with given name.
+// Nil will be return if such node does not exist.
+func (l *LiteralCode) FindReplaceRuleSet(name string) *LiteralEntry {
+	for _, ruleSet := range l.ReplaceRuleSets {
+		if ruleSet.Name == name {
+			return ruleSet
+		}
+	}
+	return nil
+}
+
+func (l *LiteralCode) resolveEntryReplaceRuleSets(entry *LiteralEntry) (err error) {
+	if len(entry.ReplaceRuleSetNames) == 0 {
+		return nil
+	}
+	resolvedRules := entry.replaceRules
+	for _, name := range entry.ReplaceRuleSetNames {
+		ruleSet := l.FindReplaceRuleSet(name)
+		if nil == ruleSet {
+			return fmt.Errorf("replace rule set not found: %v (entry: %v)", name, entry.TitleText)
+		}
+		resolvedRules = append(resolvedRules, ruleSet.replaceRules...)
+	}
+	entry.replaceRules = resolvedRules
+	return nil
+}
+
+// ResolveReplaceRuleSets attach rules of referenced replace rule sets to literal entries.
+// Rules defined in entry are placed before rules from rule sets so that they take precedence.
+// Resolved rules are pushed down to child entries which have no replace rules.
+func (l *LiteralCode) ResolveReplaceRuleSets() (err error) {
+	for _, entry := range l.LiteralConstants {
+		if err = l.resolveEntryReplaceRuleSets(entry); nil != err {
+			return
+		}
+	}
+	for _, entry := range l.LiteralConstants {
+		if len(entry.ReplaceRuleSetNames) > 0 {
+			entry.PushDownReplaceRules()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package literalcodegen
+
+import (
+	"testing"
+)
+
+func TestResolveReplaceRuleSets(t *testing.T) {
+	setRule := &ReplaceRule{LiteralTrap: "tbl_", LiteralMatch: true}
+	ownRule := &ReplaceRule{LiteralTrap: "raw", LiteralMatch: true}
+	code := &LiteralCode{}
+	ruleSet := code.NewReplaceRuleSet("tbl")
+	ruleSet.replaceRules = []*ReplaceRule{setRule}
+	parent := code.NewLiteralConstant()
+	parent.appendReplaceRuleSetName("tbl")
+	inherited := code.NewLiteralConstant()
+	inherited.attachToParent(parent)
+	grandChild := code.NewLiteralConstant()
+	grandChild.attachToParent(inherited)
+	owned := code.NewLiteralConstant()
+	owned.attachToParent(parent)
+	owned.replaceRules = []*ReplaceRule{ownRule}
+	standalone := code.NewLiteralConstant()
+	if err := code.ResolveReplaceRuleSets(); nil != err {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		name   string
+		entry  *LiteralEntry
+		expect []*ReplaceRule
+	}{
+		{"parent", parent, []*ReplaceRule{setRule}},
+		{"child without rules", inherited, []*ReplaceRule{setRule}},
+		{"grand child without rules", grandChild, []*ReplaceRule{setRule}},
+		{"child with own rules", owned, []*ReplaceRule{ownRule}},
+		{"entry without rule set", standalone, nil},
+	}
+	for _, tt := range tests {
+		if len(tt.entry.replaceRules) != len(tt.expect) {
+			t.Errorf("%s: expecting %d rules but having %d", tt.name, len(tt.expect), len(tt.entry.replaceRules))
+			continue
+		}
+		for idx, rule := range tt.entry.replaceRules {
+			if rule != tt.expect[idx] {
+				t.Errorf("%s: rule %d: expecting %p but having %p", tt.name, idx, tt.expect[idx], rule)
+			}
+		}
+	}
+}
+
+func TestResolveReplaceRuleSetsNotFound(t *testing.T) {
+	code := &LiteralCode{}
+	entry := code.NewLiteralConstant()
+	entry.appendReplaceRuleSetName("missing")
+	if err := code.ResolveReplaceRuleSets(); nil == err {
+		t.Errorf("expecting error of missing rule set")
+	}
+}
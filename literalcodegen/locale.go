@@ -0,0 +1,339 @@
+package literalcodegen
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultLocale is the locale of entry content when entry has locale variants
+// but locale of entry is not given.
+const DefaultLocale = "en"
+
+// LanguageFilterArgLocale is the key of code block argument which tags
+// content with locale (eg: `text lang=de`).
+const LanguageFilterArgLocale = "lang"
+
+// LocaleLookupSymbolSuffix is appended to entry name to form the name of
+// generated locale lookup function.
+const LocaleLookupSymbolSuffix = "ForLocale"
+
+// Kinds of locale lookup function.
+const (
+	// LocaleLookupString generate lookup function taking locale string.
+	LocaleLookupString = "string"
+
+	// LocaleLookupTag generate lookup function taking language.Tag.
+	LocaleLookupTag = "tag"
+)
+
+// splitLocaleFilterArg extract locale from code block arguments.
+func splitLocaleFilterArg(filterArgs []string) (locale string, remainArgs []string) {
+	prefix := LanguageFilterArgLocale + "="
+	for _, arg := range filterArgs {
+		if strings.HasPrefix(arg, prefix) {
+			locale = strings.TrimPrefix(arg, prefix)
+		} else {
+			remainArgs = append(remainArgs, arg)
+		}
+	}
+	return
+}
+
+func canonicalLocale(locale string) (result string, err error) {
+	tag, err := language.Parse(locale)
+	if nil != err {
+		return "", fmt.Errorf("invalid locale %q: %v", locale, err)
+	}
+	return tag.String(), nil
+}
+
+// localeSymbolSuffix convert locale into symbol suffix (eg: `zh-TW` into `ZhTW`).
+func localeSymbolSuffix(locale string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(locale, func(r rune) bool { return (r == '-') || (r == '_') }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+func (entry *LiteralEntry) appendLocaleLookupText(v string) {
+	if entry.LocaleLookupName == "" {
+		entry.LocaleLookupName = v
+	} else {
+		entry.LocaleLookupKind = v
+	}
+}
+
+// localeVariant return variant of entry for given locale. New variant will be
+// allocated from code if such variant does not exist. The locale of first
+// content block becomes locale of entry if locale of entry is not given.
+func (entry *LiteralEntry) localeVariant(code *LiteralCode, locale string) *LiteralEntry {
+	if (entry.Locale == "") && (len(entry.Content) == 0) && (len(entry.LocaleVariants) == 0) {
+		entry.Locale = locale
+	}
+	if locale == entry.Locale {
+		return entry
+	}
+	for _, variant := range entry.LocaleVariants {
+		if variant.Locale == locale {
+			return variant
+		}
+	}
+	variant := code.NewLiteralConstant()
+	variant.copyOptionsFrom(entry)
+	variant.TitleText = entry.TitleText + " [" + locale + "]"
+	variant.TitleLocation = entry.TitleLocation
+	variant.LevelDepth = entry.LevelDepth
+	variant.Locale = locale
+	variant.LocaleVariantOf = entry
+	entry.LocaleVariants = append(entry.LocaleVariants, variant)
+	return variant
+}
+
+func (entry *LiteralEntry) isLocalized() bool {
+	return (nil == entry.LocaleVariantOf) && ((entry.Locale != "") || (len(entry.LocaleVariants) > 0))
+}
+
+// Locales return locales of entry and its variants. Default locale is the
+// first one. Empty result is returned if entry is not localized.
+func (entry *LiteralEntry) Locales() (locales []string) {
+	if !entry.isLocalized() {
+		return nil
+	}
+	locales = append(locales, entry.Locale)
+	for _, variant := range entry.LocaleVariants {
+		locales = append(locales, variant.Locale)
+	}
+	return
+}
+
+func (entry *LiteralEntry) resolveLocaleVariants() (err error) {
+	if entry.Locale == "" {
+		entry.Locale = DefaultLocale
+	}
+	if entry.Locale, err = canonicalLocale(entry.Locale); nil != err {
+		return fmt.Errorf("%v: %v (entry: %v)", entry.TitleLocation, err, entry.TitleText)
+	}
+	if (entry.TranslationMode != TranslateAsConst) && (entry.TranslationMode != TranslateAsBuilder) {
+		return fmt.Errorf("%v: locale variants are only supported by const and builder entries (entry: %v)", entry.TitleLocation, entry.TitleText)
+	}
+	switch entry.LocaleLookupKind {
+	case "":
+		entry.LocaleLookupKind = LocaleLookupString
+	case LocaleLookupString, LocaleLookupTag:
+	default:
+		return fmt.Errorf("%v: unknown kind of locale lookup: %q (entry: %v)", entry.TitleLocation, entry.LocaleLookupKind, entry.TitleText)
+	}
+	seen := map[string]struct{}{entry.Locale: {}}
+	for _, variant := range entry.LocaleVariants {
+		if variant.Locale, err = canonicalLocale(variant.Locale); nil != err {
+			return fmt.Errorf("%v: %v (entry: %v)", variant.TitleLocation, err, variant.TitleText)
+		}
+		if _, ok := seen[variant.Locale]; ok {
+			return fmt.Errorf("%v: duplicated locale variant: %s (entry: %v)", variant.TitleLocation, variant.Locale, entry.TitleText)
+		}
+		seen[variant.Locale] = struct{}{}
+		variant.TranslationMode = entry.TranslationMode
+		variant.Parameters = entry.Parameters
+		if nil == variant.BuilderPrepare {
+			variant.BuilderPrepare = entry.BuilderPrepare
+		}
+		if nil == variant.replaceRules {
+			variant.replaceRules = entry.replaceRules
+		}
+		if variant.LanguageType == "" {
+			variant.LanguageType = entry.LanguageType
+			variant.LanguageFilterArgs = entry.LanguageFilterArgs
+		}
+	}
+	return nil
+}
+
+// nameLocaleVariants derive names of variants and lookup function from name
+// of entry. Names are derived on generation since external filters may
+// rename entries.
+func (entry *LiteralEntry) nameLocaleVariants() {
+	if entry.LocaleLookupName == "" {
+		entry.LocaleLookupName = entry.Name + LocaleLookupSymbolSuffix
+	}
+	for _, variant := range entry.LocaleVariants {
+		if variant.Name == "" {
+			variant.Name = entry.Name + localeSymbolSuffix(variant.Locale)
+		}
+	}
+}
+
+// isLocaleVariantChild checks if entry is a child heading which only
+// provides content of parent in locale given by `locale` option. Children
+// having name or locale variants of their own are standalone entries.
+func (entry *LiteralEntry) isLocaleVariantChild() bool {
+	return entry.localeFromOption && (nil != entry.ParentEntry) && (nil == entry.LocaleVariantOf) &&
+		(entry.Name == "") && (len(entry.LocaleVariants) == 0)
+}
+
+// ResolveLocaleVariants link child entries tagged with `locale` option to
+// their parents and set up names and options of locale variants.
+func (l *LiteralCode) ResolveLocaleVariants() (err error) {
+	for _, entry := range l.LiteralConstants {
+		if !entry.isLocaleVariantChild() {
+			continue
+		}
+		parent := entry.ParentEntry
+		entry.LocaleVariantOf = parent
+		parent.LocaleVariants = append(parent.LocaleVariants, entry)
+	}
+	for _, entry := range l.LiteralConstants {
+		if !entry.isLocalized() {
+			continue
+		}
+		if err = entry.resolveLocaleVariants(); nil != err {
+			return
+		}
+	}
+	return nil
+}
+
+// MissingLocales return locales which are used by other localized entries but
+// not provided by given entry. Missing locales fall back to default locale.
+func (l *LiteralCode) MissingLocales() (missing map[*LiteralEntry][]string) {
+	allLocales := make(map[string]struct{})
+	for _, entry := range l.LiteralConstants {
+		for _, locale := range entry.Locales() {
+			allLocales[locale] = struct{}{}
+		}
+	}
+	missing = make(map[*LiteralEntry][]string)
+	for _, entry := range l.LiteralConstants {
+		locales := entry.Locales()
+		if len(locales) == 0 {
+			continue
+		}
+		provided := make(map[string]struct{})
+		for _, locale := range locales {
+			provided[locale] = struct{}{}
+		}
+		var absent []string
+		for locale := range allLocales {
+			if _, ok := provided[locale]; !ok {
+				absent = append(absent, locale)
+			}
+		}
+		if len(absent) > 0 {
+			sort.Strings(absent)
+			missing[entry] = absent
+		}
+	}
+	return
+}
+
+func logMissingLocales(code *LiteralCode) {
+	missing := code.MissingLocales()
+	for _, entry := range code.LiteralConstants {
+		if absent, ok := missing[entry]; ok {
+			log.Printf("WARN: %v: entry [%s] missing locale variants: %s (fall back to %s)", entry.TitleLocation, entry.TitleText, strings.Join(absent, ", "), entry.Locale)
+		}
+	}
+}
+
+// parameterNames return names of builder parameters in definition order.
+func parameterNames(params []string) (names []string) {
+	for _, param := range params {
+		param = strings.TrimSpace(param)
+		idx := strings.LastIndexAny(param, " \t")
+		if idx < 0 {
+			continue
+		}
+		for _, name := range strings.Split(param[:idx], ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return
+}
+
+func (entry *LiteralEntry) variantSelectorSignature() (resultType, argsCode string) {
+	resultType = "string"
+	if entry.IsSplitContent() {
+		resultType = "[]string"
+	}
+	if entry.TranslationMode == TranslateAsBuilder {
+		argsCode = "(" + strings.Join(parameterNames(entry.Parameters), ", ") + ")"
+	}
+	return
+}
+
+func (entry *LiteralEntry) variantSelectorParameters(firstParam string) string {
+	params := []string{firstParam}
+	if entry.TranslationMode == TranslateAsBuilder {
+		params = append(params, entry.Parameters...)
+	}
+	return strings.Join(params, ", ")
+}
+
+func generateLocaleLookupByString(fp *os.File, entry *LiteralEntry) (err error) {
+	resultType, argsCode := entry.variantSelectorSignature()
+	var b strings.Builder
+	b.WriteString("// " + entry.LocaleLookupName + " return " + entry.Name + " in given locale. Subtags of locale are dropped\n")
+	b.WriteString("// from the end until a variant is found (eg: \"de-AT\" falls back to \"de\").\n")
+	b.WriteString("// Variant of " + strconv.Quote(entry.Locale) + " is returned if none matches.\n")
+	b.WriteString("func " + entry.LocaleLookupName + "(" + entry.variantSelectorParameters("locale string") + ") " + resultType + " {\n")
+	b.WriteString("\tlocale = strings.ToLower(strings.Replace(locale, \"_\", \"-\", -1))\n")
+	b.WriteString("\tfor locale != \"\" {\n")
+	b.WriteString("\t\tswitch locale {\n")
+	for _, variant := range append([]*LiteralEntry{entry}, entry.LocaleVariants...) {
+		b.WriteString("\t\tcase " + strconv.Quote(strings.ToLower(variant.Locale)) + ":\n")
+		b.WriteString("\t\t\treturn " + variant.Name + argsCode + "\n")
+	}
+	b.WriteString("\t\t}\n")
+	b.WriteString("\t\tidx := strings.LastIndexByte(locale, '-')\n")
+	b.WriteString("\t\tif idx < 0 {\n\t\t\tbreak\n\t\t}\n")
+	b.WriteString("\t\tlocale = locale[:idx]\n")
+	b.WriteString("\t}\n")
+	b.WriteString("\treturn " + entry.Name + argsCode + "\n")
+	b.WriteString("}\n\n")
+	_, err = fp.WriteString(b.String())
+	return
+}
+
+func generateLocaleLookupByTag(fp *os.File, entry *LiteralEntry) (err error) {
+	resultType, argsCode := entry.variantSelectorSignature()
+	matcherName := entry.LocaleLookupName + "Matcher"
+	var b strings.Builder
+	b.WriteString("var " + matcherName + " = language.NewMatcher([]language.Tag{\n")
+	for _, locale := range entry.Locales() {
+		b.WriteString("\tlanguage.MustParse(" + strconv.Quote(locale) + "),\n")
+	}
+	b.WriteString("})\n\n")
+	b.WriteString("// " + entry.LocaleLookupName + " return " + entry.Name + " in locale which best matches given tag.\n")
+	b.WriteString("// Variant of " + strconv.Quote(entry.Locale) + " is returned if none matches.\n")
+	b.WriteString("func " + entry.LocaleLookupName + "(" + entry.variantSelectorParameters("tag language.Tag") + ") " + resultType + " {\n")
+	b.WriteString("\t_, idx, _ := " + matcherName + ".Match(tag)\n")
+	b.WriteString("\tswitch idx {\n")
+	for idx, variant := range entry.LocaleVariants {
+		b.WriteString("\tcase " + strconv.Itoa(idx+1) + ":\n")
+		b.WriteString("\t\treturn " + variant.Name + argsCode + "\n")
+	}
+	b.WriteString("\t}\n")
+	b.WriteString("\treturn " + entry.Name + argsCode + "\n")
+	b.WriteString("}\n\n")
+	_, err = fp.WriteString(b.String())
+	return
+}
+
+// generateLocaleLookup generate lookup function of entry with locale variants.
+func generateLocaleLookup(fp *os.File, entry *LiteralEntry) (err error) {
+	if !entry.isLocalized() {
+		return nil
+	}
+	if entry.LocaleLookupKind == LocaleLookupTag {
+		return generateLocaleLookupByTag(fp, entry)
+	}
+	return generateLocaleLookupByString(fp, entry)
+}
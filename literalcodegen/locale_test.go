@@ -0,0 +1,147 @@
+package literalcodegen
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+// parseMarkdownText parse given Markdown text with ParseMarkdown.
+func parseMarkdownText(t *testing.T, text string) (code *LiteralCode, err error) {
+	fp, err := ioutil.TempFile("", "literalcodegen-test-*.md")
+	if nil != err {
+		t.Fatalf("cannot create temporary file: %v", err)
+	}
+	defer os.Remove(fp.Name())
+	if _, err = fp.WriteString(text); nil != err {
+		fp.Close()
+		t.Fatalf("cannot write temporary file: %v", err)
+	}
+	if err = fp.Close(); nil != err {
+		t.Fatalf("cannot close temporary file: %v", err)
+	}
+	return ParseMarkdown(fp.Name())
+}
+
+func findLiteralEntry(code *LiteralCode, titleText string) *LiteralEntry {
+	for _, entry := range code.LiteralConstants {
+		if entry.TitleText == titleText {
+			return entry
+		}
+	}
+	return nil
+}
+
+const localeTestMarkdown = "# Greeting\n\n" +
+	"* `const`: `greetingText`\n\n" +
+	"```text\nHello\n```\n\n" +
+	"```text lang=de\nHallo\n```\n\n" +
+	"```text lang=zh_tw\n你好\n```\n\n" +
+	"# Welcome\n\n" +
+	"* `builder`: `welcomeText`, `name string`\n" +
+	"* `locale`: `en`\n\n" +
+	"```text\nWelcome\n```\n\n" +
+	"## German\n\n" +
+	"* `locale`: `de`\n\n" +
+	"```text\nWillkommen\n```\n\n" +
+	"## Farewell\n\n" +
+	"* `const`: `farewellText`\n" +
+	"* `locale`: `de`\n\n" +
+	"```text\nTschüss\n```\n\n" +
+	"## Thanks\n\n" +
+	"* `locale`: `fr`\n\n" +
+	"```text\nMerci\n```\n\n" +
+	"```text lang=de\nDanke\n```\n\n" +
+	"# Plain\n\n" +
+	"* `const`: `plainText`\n\n" +
+	"```text\nplain\n```\n"
+
+func TestResolveLocaleVariants(t *testing.T) {
+	code, err := parseMarkdownText(t, localeTestMarkdown)
+	if nil != err {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	nameVariantEntries(code.LiteralConstants)
+	tests := []struct {
+		titleText    string
+		locales      []string
+		variantNames []string
+		variantOf    string
+	}{
+		{"Greeting", []string{"en", "de", "zh-TW"}, []string{"greetingTextDe", "greetingTextZhTW"}, ""},
+		{"Welcome", []string{"en", "de"}, []string{"welcomeTextDe"}, ""},
+		{"German", nil, nil, "Welcome"},
+		{"Farewell", []string{"de"}, nil, ""},
+		{"Thanks", []string{"fr", "de"}, nil, ""},
+		{"Plain", nil, nil, ""},
+	}
+	for _, tt := range tests {
+		entry := findLiteralEntry(code, tt.titleText)
+		if nil == entry {
+			t.Errorf("%s: entry not found", tt.titleText)
+			continue
+		}
+		if locales := strings.Join(entry.Locales(), ","); locales != strings.Join(tt.locales, ",") {
+			t.Errorf("%s: expecting locales %v but having %v", tt.titleText, tt.locales, entry.Locales())
+		}
+		var variantNames []string
+		for _, variant := range entry.LocaleVariants {
+			variantNames = append(variantNames, variant.Name)
+		}
+		if (entry.Name != "") && (strings.Join(variantNames, ",") != strings.Join(tt.variantNames, ",")) {
+			t.Errorf("%s: expecting variant names %v but having %v", tt.titleText, tt.variantNames, variantNames)
+		}
+		variantOf := ""
+		if nil != entry.LocaleVariantOf {
+			variantOf = entry.LocaleVariantOf.TitleText
+		}
+		if variantOf != tt.variantOf {
+			t.Errorf("%s: expecting variant of %q but having %q", tt.titleText, tt.variantOf, variantOf)
+		}
+	}
+	if name := findLiteralEntry(code, "Welcome").LocaleLookupName; name != "welcomeText"+LocaleLookupSymbolSuffix {
+		t.Errorf("unexpected name of lookup function: %q", name)
+	}
+}
+
+func TestResolveLocaleVariantsError(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{
+			name: "duplicated locale",
+			text: "# A\n\n* `const`: `a`\n* `locale`: `de`\n\n```text\nx\n```\n\n## B\n\n* `locale`: `de`\n\n```text\ny\n```\n",
+		},
+		{
+			name: "invalid locale",
+			text: "# A\n\n* `const`: `a`\n\n```text\nx\n```\n\n```text lang=not_a_locale!\ny\n```\n",
+		},
+		{
+			name: "unknown lookup kind",
+			text: "# A\n\n* `const`: `a`\n* `locale-lookup`: `aFor`, `int`\n\n```text\nx\n```\n\n```text lang=de\ny\n```\n",
+		},
+	}
+	for _, tt := range tests {
+		if _, err := parseMarkdownText(t, tt.text); nil == err {
+			t.Errorf("%s: expecting error", tt.name)
+		}
+	}
+}
+
+func TestLocaleSymbolSuffix(t *testing.T) {
+	tests := []struct {
+		locale string
+		expect string
+	}{
+		{"de", "De"},
+		{"zh-TW", "ZhTW"},
+		{"sr_Latn_RS", "SrLatnRS"},
+	}
+	for _, tt := range tests {
+		if result := localeSymbolSuffix(tt.locale); result != tt.expect {
+			t.Errorf("%s: expecting %q but having %q", tt.locale, tt.expect, result)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package literalcodegen
+
+import (
+	"strconv"
+)
+
+// SourceLocation points out a line in Markdown input.
+type SourceLocation struct {
+	FilePath string
+	Line     int
+}
+
+// IsZero return true if the location is not set.
+func (loc SourceLocation) IsZero() bool {
+	return loc.Line == 0
+}
+
+func (loc SourceLocation) offset(lines int) SourceLocation {
+	if loc.IsZero() {
+		return loc
+	}
+	return SourceLocation{
+		FilePath: loc.FilePath,
+		Line:     loc.Line + lines,
+	}
+}
+
+func (loc SourceLocation) String() string {
+	if loc.IsZero() {
+		if loc.FilePath == "" {
+			return "(unknown location)"
+		}
+		return loc.FilePath
+	}
+	return loc.FilePath + ":" + strconv.FormatInt(int64(loc.Line), 10)
+}
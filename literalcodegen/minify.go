@@ -0,0 +1,107 @@
+package literalcodegen
+
+import (
+	"strings"
+)
+
+// Minification levels of HTML, CSS and JavaScript language filters.
+const (
+	minifyConservative = "conservative"
+	minifyAggressive   = "aggressive"
+)
+
+func parseMinifyLevel(filterArgs LanguageFilterArgs) string {
+	return filterArgs.Choice(minifyConservative, minifyConservative, minifyAggressive)
+}
+
+type minifyTokenType int
+
+const (
+	minifyTokenWhitespace minifyTokenType = iota
+	minifyTokenComment
+	minifyTokenQuoted
+	minifyTokenWord
+	minifyTokenPunctuation
+)
+
+type minifyToken struct {
+	Type minifyTokenType
+	Text string
+}
+
+func (tok *minifyToken) hasNewLine() bool {
+	return strings.Contains(tok.Text, "\n")
+}
+
+func (tok *minifyToken) isPunctuationOf(punctuations string) bool {
+	return (tok.Type == minifyTokenPunctuation) && strings.Contains(punctuations, tok.Text)
+}
+
+func isMinifySpace(ch byte) bool {
+	return (ch == ' ') || (ch == '\t') || (ch == '\n') || (ch == '\r') || (ch == '\f') || (ch == '\v')
+}
+
+// scanMinifyQuoted return end position of quoted text starts at given position.
+// Backslash escapes the following character. Returns -1 if not terminated.
+func scanMinifyQuoted(text string, start int) int {
+	quote := text[start]
+	for idx := start + 1; idx < len(text); idx++ {
+		switch text[idx] {
+		case '\\':
+			idx++
+		case quote:
+			return idx + 1
+		}
+	}
+	return -1
+}
+
+func scanMinifySpace(text string, start int) int {
+	idx := start
+	for (idx < len(text)) && isMinifySpace(text[idx]) {
+		idx++
+	}
+	return idx
+}
+
+// significantMinifyTokenAround return the nearest non-white-space and
+// non-comment token before and after given index of white space token.
+func significantMinifyTokenAround(tokens []minifyToken, index int) (prev, next *minifyToken) {
+	for idx := index - 1; idx >= 0; idx-- {
+		if t := tokens[idx].Type; (t != minifyTokenWhitespace) && (t != minifyTokenComment) {
+			prev = &tokens[idx]
+			break
+		}
+	}
+	for idx := index + 1; idx < len(tokens); idx++ {
+		if t := tokens[idx].Type; (t != minifyTokenWhitespace) && (t != minifyTokenComment) {
+			next = &tokens[idx]
+			break
+		}
+	}
+	return
+}
+
+// mergeMinifySpaces replace comments (except kept ones) with white space and
+// merge adjacent white spaces into one token. Comments spanning lines are
+// replaced with new line to keep line breaks.
+func mergeMinifySpaces(tokens []minifyToken, keepComment func(tok *minifyToken) bool) (result []minifyToken) {
+	for _, tok := range tokens {
+		if (tok.Type == minifyTokenComment) && !keepComment(&tok) {
+			replacement := " "
+			if tok.hasNewLine() {
+				replacement = "\n"
+			}
+			tok = minifyToken{
+				Type: minifyTokenWhitespace,
+				Text: replacement,
+			}
+		}
+		if lastIndex := len(result) - 1; (tok.Type == minifyTokenWhitespace) && (lastIndex >= 0) && (result[lastIndex].Type == minifyTokenWhitespace) {
+			result[lastIndex].Text = result[lastIndex].Text + tok.Text
+			continue
+		}
+		result = append(result, tok)
+	}
+	return
+}
@@ -12,6 +12,12 @@ import (
 // TextTrapHeadingCode is the trapping constant string for detecting heading code block
 const TextTrapHeadingCode = "Heading Code"
 
+// TextTrapReplaceRuleSet is the trapping prefix for detecting named replace rule set block
+const TextTrapReplaceRuleSet = "Replace Rule Set:"
+
+// TextTrapVariables is the trapping constant string for detecting generation-time variables block
+const TextTrapVariables = "Variables"
+
 // TextTrapBuilderPrepare is trapping constant for prepare code of builder function.
 const TextTrapBuilderPrepare = "- Builder Prepare"
 
@@ -29,13 +35,28 @@ type markdownParseSpace struct {
 	currentChain  [MaxHeadingDepth]*LiteralEntry
 	replaceRule   *ReplaceRule
 	replaceTarget *ReplaceTarget
+
+	variableSection bool
+	variableName    string
+
+	filePath  string
+	inlineMap [2]int
 }
 
-func newMarkdownParseSpace() (result *markdownParseSpace) {
-	result = &markdownParseSpace{}
+func newMarkdownParseSpace(filePath string) (result *markdownParseSpace) {
+	result = &markdownParseSpace{
+		filePath: filePath,
+	}
 	return
 }
 
+func (w *markdownParseSpace) locationOf(lineMap [2]int) SourceLocation {
+	return SourceLocation{
+		FilePath: w.filePath,
+		Line:     lineMap[0] + 1,
+	}
+}
+
 func (w *markdownParseSpace) wipeChainFrom(index int) {
 	for idx := index; idx < MaxHeadingDepth; idx++ {
 		w.currentChain[idx] = nil
@@ -44,12 +65,29 @@ func (w *markdownParseSpace) wipeChainFrom(index int) {
 
 func (w *markdownParseSpace) stateHeading1(token markdown.Token) (nextCallable markdownParseCallable, err error) {
 	if textToken, ok := token.(*markdown.Inline); ok {
+		w.variableSection = false
 		if textToken.Content == TextTrapHeadingCode {
 			w.currentNode = w.result.NewHeadingCode()
+			w.currentNode.TitleLocation = w.locationOf(textToken.Map)
 			log.Printf("having heading code node")
+		} else if strings.HasPrefix(textToken.Content, TextTrapReplaceRuleSet) {
+			name := strings.TrimSpace(strings.TrimPrefix(textToken.Content, TextTrapReplaceRuleSet))
+			if name == "" {
+				return nil, fmt.Errorf("name of replace rule set is required (L1-H): %v", textToken.Content)
+			}
+			node := w.result.NewReplaceRuleSet(name)
+			node.TitleText = textToken.Content
+			node.TitleLocation = w.locationOf(textToken.Map)
+			w.currentNode = node
+			log.Printf("having replace rule set node: %v", name)
+		} else if textToken.Content == TextTrapVariables {
+			w.currentNode = nil
+			w.variableSection = true
+			log.Printf("having variables node")
 		} else {
 			node := w.result.NewLiteralConstant()
 			node.TitleText = textToken.Content
+			node.TitleLocation = w.locationOf(textToken.Map)
 			w.currentNode = node
 			w.currentChain[0] = node
 			log.Printf("having literal constant node (level=1)")
@@ -71,6 +109,7 @@ func (w *markdownParseSpace) stateHeading2(token markdown.Token) (nextCallable m
 			}
 			node := w.currentNode.GetBuilderPrepareNode()
 			node.TitleText = textToken.Content
+			node.TitleLocation = w.locationOf(textToken.Map)
 			w.currentNode = node
 			return w.stateZero, nil
 		case TextTrapContentCode:
@@ -95,6 +134,7 @@ func (w *markdownParseSpace) stateHeadingN(token markdown.Token) (nextCallable m
 	if textToken, ok := token.(*markdown.Inline); ok {
 		node := w.result.NewLiteralConstant()
 		node.TitleText = textToken.Content
+		node.TitleLocation = w.locationOf(textToken.Map)
 		parentNode := w.currentChain[0]
 		for idx := 1; idx < MaxHeadingDepth; idx++ {
 			if nil != w.currentChain[idx] {
@@ -135,14 +175,26 @@ func (w *markdownParseSpace) stateReplaceRuleZero(token markdown.Token) (nextCal
 		return
 	}
 	txt := node.Content
-	if nil == w.replaceRule.RegexTrap {
-		err = w.replaceRule.setRegexTrap(txt)
+	if !w.replaceRule.hasTrap() {
+		err = w.replaceRule.setTrap(txt)
 		w.replaceTarget = nil
+	} else if (nil == w.replaceTarget) && isEscaperDeclaration(txt) {
+		target := w.replaceRule.lastTarget()
+		if nil == target {
+			return nil, fmt.Errorf("escaper must follow a replace target: %v", txt)
+		}
+		err = target.setEscaper(parseEscaperDeclaration(txt))
+	} else if w.replaceRule.LiteralMatch {
+		if len(w.replaceRule.Targets) > 0 {
+			log.Printf("WARN: literal replace rule accepts only one replacement code, skipped: %v", txt)
+			return
+		}
+		w.replaceRule.addTarget().setReplacementCode(txt, w.locationOf(w.inlineMap))
 	} else if nil == w.replaceTarget {
 		w.replaceTarget = w.replaceRule.addTarget()
 		err = w.replaceTarget.setGroupIndex(txt)
 	} else {
-		w.replaceTarget.setReplacementCode(txt)
+		w.replaceTarget.setReplacementCode(txt, w.locationOf(w.inlineMap))
 		w.replaceTarget = nil
 	}
 	return
@@ -151,9 +203,12 @@ func (w *markdownParseSpace) stateReplaceRuleZero(token markdown.Token) (nextCal
 func (w *markdownParseSpace) stateReplaceRule(token markdown.Token) (nextCallable markdownParseCallable, err error) {
 	switch node := token.(type) {
 	case *markdown.Inline:
-		w.feedTokens(w.stateReplaceRuleZero, node.Children)
+		w.inlineMap = node.Map
+		if err = w.feedTokens(w.stateReplaceRuleZero, node.Children); nil != err {
+			return nil, fmt.Errorf("%v: %v", w.locationOf(node.Map), err)
+		}
 	case *markdown.BulletListClose:
-		if nil != w.replaceRule.RegexTrap {
+		if w.replaceRule.hasTrap() {
 			w.replaceRule.sortTarget()
 			w.currentNode.appendReplaceRule(w.replaceRule)
 		}
@@ -191,6 +246,152 @@ func (w *markdownParseSpace) stateOptionItemBuilder(token markdown.Token) (nextC
 	return
 }
 
+func (w *markdownParseSpace) stateOptionItemReplaceRuleSet(token markdown.Token) (nextCallable markdownParseCallable, err error) {
+	node, ok := token.(*markdown.CodeInline)
+	if !ok {
+		log.Printf("- skipped: option (L1-0-replace-rule-set): %T, %v", token, token)
+		return
+	}
+	w.currentNode.appendReplaceRuleSetName(node.Content)
+	return
+}
+
+func (w *markdownParseSpace) stateOptionItemTimeLayout(token markdown.Token) (nextCallable markdownParseCallable, err error) {
+	node, ok := token.(*markdown.CodeInline)
+	if !ok {
+		log.Printf("- skipped: option (L1-0-time-layout): %T, %v", token, token)
+		return
+	}
+	w.currentNode.TimeLayout = node.Content
+	return
+}
+
+func (w *markdownParseSpace) stateOptionItemFilterCommand(token markdown.Token) (nextCallable markdownParseCallable, err error) {
+	node, ok := token.(*markdown.CodeInline)
+	if !ok {
+		log.Printf("- skipped: option (L1-0-filter-command): %T, %v", token, token)
+		return
+	}
+	w.currentNode.FilterCommand = node.Content
+	return
+}
+
+// optionValueError is an error of option value which aborts parsing. Other
+// malformed options are skipped.
+type optionValueError struct {
+	option string
+	err    error
+}
+
+func (e *optionValueError) Error() string {
+	return "invalid value of " + e.option + " option: " + e.err.Error()
+}
+
+func (w *markdownParseSpace) stateOptionItemTabWidth(token markdown.Token) (nextCallable markdownParseCallable, err error) {
+	node, ok := token.(*markdown.CodeInline)
+	if !ok {
+		log.Printf("- skipped: option (L1-0-expand-tabs): %T, %v", token, token)
+		return
+	}
+	if err = w.currentNode.setTabWidthText(node.Content); nil != err {
+		err = &optionValueError{option: "expand-tabs", err: err}
+	}
+	return
+}
+
+func (w *markdownParseSpace) stateOptionItemLineEnding(token markdown.Token) (nextCallable markdownParseCallable, err error) {
+	node, ok := token.(*markdown.CodeInline)
+	if !ok {
+		log.Printf("- skipped: option (L1-0-line-ending): %T, %v", token, token)
+		return
+	}
+	if err = w.currentNode.setLineEndingText(node.Content); nil != err {
+		err = &optionValueError{option: "line-ending", err: err}
+	}
+	return
+}
+
+func (w *markdownParseSpace) stateOptionItemLocale(token markdown.Token) (nextCallable markdownParseCallable, err error) {
+	node, ok := token.(*markdown.CodeInline)
+	if !ok {
+		log.Printf("- skipped: option (L1-0-locale): %T, %v", token, token)
+		return
+	}
+	w.currentNode.Locale = strings.TrimSpace(node.Content)
+	w.currentNode.localeFromOption = true
+	return
+}
+
+func (w *markdownParseSpace) stateOptionItemLocaleLookup(token markdown.Token) (nextCallable markdownParseCallable, err error) {
+	node, ok := token.(*markdown.CodeInline)
+	if !ok {
+		log.Printf("- skipped: option (L1-0-locale-lookup): %T, %v", token, token)
+		return
+	}
+	w.currentNode.appendLocaleLookupText(strings.TrimSpace(node.Content))
+	return
+}
+
+func (w *markdownParseSpace) stateOptionItemSQLDialect(token markdown.Token) (nextCallable markdownParseCallable, err error) {
+	node, ok := token.(*markdown.CodeInline)
+	if !ok {
+		log.Printf("- skipped: option (L1-0-sql-dialect): %T, %v", token, token)
+		return
+	}
+	w.currentNode.setSQLDialectText(node.Content)
+	return
+}
+
+func (w *markdownParseSpace) stateOptionItemSQLDialects(token markdown.Token) (nextCallable markdownParseCallable, err error) {
+	node, ok := token.(*markdown.CodeInline)
+	if !ok {
+		log.Printf("- skipped: option (L1-0-sql-dialects): %T, %v", token, token)
+		return
+	}
+	w.currentNode.appendSQLDialectsText(node.Content)
+	return
+}
+
+func (w *markdownParseSpace) stateOptionItemSQLDialectSelector(token markdown.Token) (nextCallable markdownParseCallable, err error) {
+	node, ok := token.(*markdown.CodeInline)
+	if !ok {
+		log.Printf("- skipped: option (L1-0-sql-dialect-selector): %T, %v", token, token)
+		return
+	}
+	w.currentNode.SQLDialectSelectorName = strings.TrimSpace(node.Content)
+	return
+}
+
+func (w *markdownParseSpace) stateOptionItemStringerType(token markdown.Token) (nextCallable markdownParseCallable, err error) {
+	node, ok := token.(*markdown.CodeInline)
+	if !ok {
+		log.Printf("- skipped: option (L1-0-stringer-type): %T, %v", token, token)
+		return
+	}
+	w.currentNode.StringerTypes = append(w.currentNode.StringerTypes, node.Content)
+	return
+}
+
+func (w *markdownParseSpace) stateOptionItemTemplateFunc(token markdown.Token) (nextCallable markdownParseCallable, err error) {
+	node, ok := token.(*markdown.CodeInline)
+	if !ok {
+		log.Printf("- skipped: option (L1-0-template-func): %T, %v", token, token)
+		return
+	}
+	w.currentNode.appendTemplateFuncText(node.Content)
+	return
+}
+
+func (w *markdownParseSpace) stateOptionItemTemplateDelims(token markdown.Token) (nextCallable markdownParseCallable, err error) {
+	node, ok := token.(*markdown.CodeInline)
+	if !ok {
+		log.Printf("- skipped: option (L1-0-template-delims): %T, %v", token, token)
+		return
+	}
+	w.currentNode.appendTemplateDelimText(node.Content)
+	return
+}
+
 func (w *markdownParseSpace) stateOptionItemZero(token markdown.Token) (nextCallable markdownParseCallable, err error) {
 	node, ok := token.(*markdown.CodeInline)
 	if !ok {
@@ -206,9 +407,27 @@ func (w *markdownParseSpace) stateOptionItemZero(token markdown.Token) (nextCall
 	case "builder":
 		w.currentNode.TranslationMode = TranslateAsBuilder
 		nextCallable = w.stateOptionItemBuilder
+	case "regexp":
+		w.currentNode.TranslationMode = TranslateAsRegexp
+		nextCallable = w.stateOptionItemConst
+	case "template":
+		w.currentNode.TranslationMode = TranslateAsTemplate
+		nextCallable = w.stateOptionItemConst
+	case "template-func":
+		nextCallable = w.stateOptionItemTemplateFunc
+	case "template-delims":
+		nextCallable = w.stateOptionItemTemplateDelims
 	case "replace":
 		w.replaceRule = newReplaceRule()
 		// return w.stateOptionItemReplace, nil
+	case "replace-literal":
+		w.replaceRule = newLiteralReplaceRule()
+	case "replace-rule-set":
+		nextCallable = w.stateOptionItemReplaceRuleSet
+	case "time-layout":
+		nextCallable = w.stateOptionItemTimeLayout
+	case "stringer-type":
+		nextCallable = w.stateOptionItemStringerType
 	case "strip-spaces":
 		w.currentNode.TrimSpace = true
 	case "preserve-new-line":
@@ -219,6 +438,26 @@ func (w *markdownParseSpace) stateOptionItemZero(token markdown.Token) (nextCall
 		w.currentNode.TailNewLine = true
 	case "disable-language-filter":
 		w.currentNode.DisableLanguageFilter = true
+	case "filter-command":
+		nextCallable = w.stateOptionItemFilterCommand
+	case "dedent":
+		w.currentNode.Dedent = true
+	case "expand-tabs":
+		w.currentNode.TabWidth = DefaultTabWidth
+		nextCallable = w.stateOptionItemTabWidth
+	case "line-ending":
+		nextCallable = w.stateOptionItemLineEnding
+	case "locale":
+		nextCallable = w.stateOptionItemLocale
+	case "locale-lookup":
+		nextCallable = w.stateOptionItemLocaleLookup
+	case "sql-dialect":
+		w.currentNode.sqlDialectFromOption = true
+		nextCallable = w.stateOptionItemSQLDialect
+	case "sql-dialects":
+		nextCallable = w.stateOptionItemSQLDialects
+	case "sql-dialect-selector":
+		nextCallable = w.stateOptionItemSQLDialectSelector
 	default:
 		log.Printf("** unknown option command (L1-0): %v", node.Content)
 	}
@@ -228,7 +467,13 @@ func (w *markdownParseSpace) stateOptionItemZero(token markdown.Token) (nextCall
 func (w *markdownParseSpace) stateOptionItem(token markdown.Token) (nextCallable markdownParseCallable, err error) {
 	switch node := token.(type) {
 	case *markdown.Inline:
-		w.feedTokens(w.stateOptionItemZero, node.Children)
+		if err = w.feedTokens(w.stateOptionItemZero, node.Children); nil != err {
+			if _, ok := err.(*optionValueError); ok {
+				return nil, fmt.Errorf("%v: %v", w.locationOf(node.Map), err)
+			}
+			log.Printf("- skipped: option (L1): %v", err)
+			err = nil
+		}
 	case *markdown.ListItemClose:
 		return w.stateZero, nil
 	case *markdown.BulletListOpen:
@@ -239,15 +484,63 @@ func (w *markdownParseSpace) stateOptionItem(token markdown.Token) (nextCallable
 	return
 }
 
+func (w *markdownParseSpace) stateVariableItemZero(token markdown.Token) (nextCallable markdownParseCallable, err error) {
+	node, ok := token.(*markdown.CodeInline)
+	if !ok {
+		log.Printf("- skipped: variable (L1-V-0): %T, %v", token, token)
+		return
+	}
+	if w.variableName == "" {
+		w.variableName = strings.TrimSpace(node.Content)
+		return
+	}
+	w.result.SetVariable(w.variableName, node.Content)
+	w.variableName = ""
+	return
+}
+
+func (w *markdownParseSpace) stateVariableItem(token markdown.Token) (nextCallable markdownParseCallable, err error) {
+	switch node := token.(type) {
+	case *markdown.Inline:
+		w.variableName = ""
+		w.feedTokens(w.stateVariableItemZero, node.Children)
+		if w.variableName != "" {
+			w.result.SetVariable(w.variableName, "")
+			w.variableName = ""
+		}
+	case *markdown.ListItemClose:
+		return w.stateZero, nil
+	default:
+		log.Printf("- skipped variable (L1-V): %T, %#v", token, token)
+	}
+	return
+}
+
 func (w *markdownParseSpace) stateZero(token markdown.Token) (nextCallable markdownParseCallable, err error) {
 	switch node := token.(type) {
 	case *markdown.HeadingOpen:
 		return w.checkHeading(token.(*markdown.HeadingOpen))
 	case *markdown.ListItemOpen:
+		if w.variableSection {
+			return w.stateVariableItem, nil
+		}
 		return w.stateOptionItem, nil
 	case *markdown.Fence:
+		if w.variableSection {
+			log.Printf("- skipped: code block in variables section (L0): %#v", token)
+			break
+		}
 		langType, filterArgs := parseCodeBlockLanguageParams(node.Params)
-		w.currentNode.AppendContent(node.Content, langType, filterArgs)
+		targetNode := w.currentNode
+		if locale, remainArgs := splitLocaleFilterArg(filterArgs); locale != "" {
+			targetNode = targetNode.localeVariant(&w.result, locale)
+			filterArgs = remainArgs
+		}
+		if dialect, remainArgs := splitSQLDialectFilterArg(langType, filterArgs); dialect != "" {
+			targetNode = targetNode.sqlDialectVariant(&w.result, dialect)
+			filterArgs = remainArgs
+		}
+		targetNode.AppendContentAt(node.Content, langType, filterArgs, w.locationOf(node.Map).offset(1))
 	default:
 		log.Printf("- skipped: markdown (L0): %T, %#v", token, token)
 	}
@@ -290,9 +583,25 @@ func ParseMarkdown(filePath string) (code *LiteralCode, err error) {
 	md := markdown.New()
 	tokens := md.Parse(buf)
 	logMarkdownAST(tokens)
-	work := newMarkdownParseSpace()
+	work := newMarkdownParseSpace(filePath)
 	if err = work.feedTokens(work.stateZero, tokens); nil != err {
 		return
 	}
+	if err = work.result.ResolveReplaceRuleSets(); nil != err {
+		return
+	}
+	if err = work.result.ResolveLocaleVariants(); nil != err {
+		return
+	}
+	if err = checkLineEndingOptions(&work.result); nil != err {
+		return
+	}
+	logMissingLocales(&work.result)
+	if err = work.result.ResolveSQLDialectVariants(); nil != err {
+		return
+	}
+	if err = checkMissingSQLDialects(&work.result); nil != err {
+		return
+	}
 	return &work.result, nil
 }
@@ -0,0 +1,89 @@
+package literalcodegen
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+func init() {
+	RegisterLanguageFilter("regexp", LanguageFilterFunc(regexpContentFilter))
+}
+
+// stripExtendedRegexp remove white spaces and `#` comments of extended
+// (verbose) regular expression. White spaces and `#` in character classes
+// are kept. Escaped white space and `#` are taken as literal characters.
+func stripExtendedRegexp(text string) string {
+	var b strings.Builder
+	inClass := false
+	inComment := false
+	escaped := false
+	for _, ch := range text {
+		if inComment {
+			if ch == '\n' {
+				inComment = false
+			}
+			continue
+		}
+		if escaped {
+			escaped = false
+			if unicode.IsSpace(ch) {
+				b.WriteRune(ch)
+				continue
+			}
+			b.WriteRune('\\')
+			b.WriteRune(ch)
+			continue
+		}
+		switch {
+		case ch == '\\':
+			escaped = true
+			continue
+		case inClass:
+			if ch == ']' {
+				inClass = false
+			}
+		case ch == '[':
+			inClass = true
+		case ch == '#':
+			inComment = true
+			continue
+		case unicode.IsSpace(ch):
+			continue
+		}
+		b.WriteRune(ch)
+	}
+	if escaped {
+		b.WriteRune('\\')
+	}
+	return b.String()
+}
+
+func regexpContentFilter(codeContent []string, filterArgs LanguageFilterArgs) (result []string, err error) {
+	var pattern string
+	if filterArgs.Has("extended") {
+		text, _ := joinContentLines(codeContent)
+		pattern = stripExtendedRegexp(text)
+	} else {
+		pattern = strings.Join(codeContent, "")
+	}
+	if _, err = regexp.Compile(pattern); nil != err {
+		return
+	}
+	return []string{pattern}, nil
+}
+
+// regexpPattern return filtered content as regular expression pattern.
+// The pattern is compiled to make sure it is valid.
+func (entry *LiteralEntry) regexpPattern() (pattern string, err error) {
+	content, err := entry.FilteredContent()
+	if nil != err {
+		return
+	}
+	pattern = strings.Join(content, "")
+	if _, err = regexp.Compile(pattern); nil != err {
+		return "", fmt.Errorf("%v: invalid regular expression of entry [%s]: %v", entry.ContentLocation, entry.TitleText, err)
+	}
+	return pattern, nil
+}
@@ -0,0 +1,71 @@
+package literalcodegen
+
+import (
+	"testing"
+)
+
+func TestRegexpContentFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    []string
+		filterArgs []string
+		expect     string
+	}{
+		{
+			name:    "plain",
+			content: []string{"^user_", "[0-9]+$"},
+			expect:  "^user_[0-9]+$",
+		},
+		{
+			name:       "extended",
+			content:    []string{"^ (\\w+)   # name", "  \\s* = \\s*  # separator", "  (.*) $"},
+			filterArgs: []string{"extended"},
+			expect:     "^(\\w+)\\s*=\\s*(.*)$",
+		},
+		{
+			name:       "extended keeps character class",
+			content:    []string{"[ #]+ # spaces or hashes"},
+			filterArgs: []string{"extended"},
+			expect:     "[ #]+",
+		},
+		{
+			name:       "extended escaped space and hash",
+			content:    []string{"a\\ b \\# c"},
+			filterArgs: []string{"extended"},
+			expect:     "a b\\#c",
+		},
+		{
+			name:       "extended escaped bracket",
+			content:    []string{"\\[ x \\] # y"},
+			filterArgs: []string{"extended"},
+			expect:     "\\[x\\]",
+		},
+	}
+	for _, tt := range tests {
+		result, err := regexpContentFilter(tt.content, LanguageFilterArgs(tt.filterArgs))
+		if nil != err {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if (len(result) != 1) || (result[0] != tt.expect) {
+			t.Errorf("%s: expecting %q but having %q", tt.name, tt.expect, result)
+		}
+	}
+}
+
+func TestRegexpContentFilterError(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    []string
+		filterArgs []string
+	}{
+		{"unbalanced parenthesis", []string{"(a"}, nil},
+		{"comment hides closing parenthesis", []string{"(a # )"}, []string{"extended"}},
+		{"unsupported look ahead", []string{"a(?=b)"}, nil},
+	}
+	for _, tt := range tests {
+		if _, err := regexpContentFilter(tt.content, LanguageFilterArgs(tt.filterArgs)); nil == err {
+			t.Errorf("%s: expecting error", tt.name)
+		}
+	}
+}
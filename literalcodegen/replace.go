@@ -1,6 +1,7 @@
 package literalcodegen
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
 	"sort"
@@ -13,6 +14,8 @@ import (
 type ReplaceTarget struct {
 	GroupIndex      int
 	ReplacementCode string
+	Escaper         string
+	Location        SourceLocation
 }
 
 func (target *ReplaceTarget) setGroupIndex(v string) (err error) {
@@ -30,8 +33,17 @@ func (target *ReplaceTarget) setGroupIndex(v string) (err error) {
 	return nil
 }
 
-func (target *ReplaceTarget) setReplacementCode(v string) (err error) {
+func (target *ReplaceTarget) setReplacementCode(v string, location SourceLocation) (err error) {
 	target.ReplacementCode = v
+	target.Location = location
+	return nil
+}
+
+func (target *ReplaceTarget) setEscaper(v string) (err error) {
+	if v == "" {
+		return errors.New("name of escaper must not be empty")
+	}
+	target.Escaper = v
 	return nil
 }
 
@@ -42,10 +54,13 @@ func (a OrderReplaceTarget) Len() int           { return len(a) }
 func (a OrderReplaceTarget) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 func (a OrderReplaceTarget) Less(i, j int) bool { return a[i].GroupIndex < a[j].GroupIndex }
 
-// ReplaceRule represent literal replacing rule for generating builder function
+// ReplaceRule represent literal replacing rule for generating builder function.
+// The rule matches text with RegexTrap or with LiteralTrap if LiteralMatch is set.
 type ReplaceRule struct {
-	RegexTrap *regexp.Regexp
-	Targets   []*ReplaceTarget
+	RegexTrap    *regexp.Regexp
+	LiteralTrap  string
+	LiteralMatch bool
+	Targets      []*ReplaceTarget
 }
 
 func newReplaceRule() *ReplaceRule {
@@ -54,6 +69,34 @@ func newReplaceRule() *ReplaceRule {
 	}
 }
 
+func newLiteralReplaceRule() *ReplaceRule {
+	return &ReplaceRule{
+		LiteralMatch: true,
+	}
+}
+
+func (rule *ReplaceRule) hasTrap() bool {
+	if rule.LiteralMatch {
+		return rule.LiteralTrap != ""
+	}
+	return nil != rule.RegexTrap
+}
+
+func (rule *ReplaceRule) setLiteralTrap(v string) (err error) {
+	if v = strings.TrimSpace(v); v == "" {
+		return errors.New("literal trap of replace rule must not be empty")
+	}
+	rule.LiteralTrap = v
+	return nil
+}
+
+func (rule *ReplaceRule) setTrap(v string) (err error) {
+	if rule.LiteralMatch {
+		return rule.setLiteralTrap(v)
+	}
+	return rule.setRegexTrap(v)
+}
+
 func (rule *ReplaceRule) setRegexTrap(v string) (err error) {
 	v = strings.TrimSpace(v)
 	regexRule, err := regexp.Compile(v)
@@ -70,11 +113,48 @@ func (rule *ReplaceRule) addTarget() (target *ReplaceTarget) {
 	return target
 }
 
+func (rule *ReplaceRule) lastTarget() *ReplaceTarget {
+	if len(rule.Targets) == 0 {
+		return nil
+	}
+	return rule.Targets[len(rule.Targets)-1]
+}
+
 func (rule *ReplaceRule) sortTarget() {
 	sort.Sort(OrderReplaceTarget(rule.Targets))
 }
 
+func (rule *ReplaceRule) doLiteralReplace(textLine string) (results []*ReplaceResult, err error) {
+	if len(rule.Targets) != 1 {
+		err = fmt.Errorf("literal replace rule requires exactly one target (%d): rule=%q, %v", len(rule.Targets), rule.LiteralTrap, textLine)
+		return
+	}
+	target := rule.Targets[0]
+	trapLength := len(rule.LiteralTrap)
+	remain := textLine
+	for {
+		idx := strings.Index(remain, rule.LiteralTrap)
+		if idx < 0 {
+			break
+		}
+		results = append(results, &ReplaceResult{
+			PrefixLiteral: remain[:idx],
+			ReplacedCode:  target.ReplacementCode,
+			Escaper:       target.Escaper,
+			Location:      target.Location,
+		})
+		remain = remain[idx+trapLength:]
+	}
+	if len(results) > 0 {
+		results[len(results)-1].SuffixLiteral = remain
+	}
+	return results, nil
+}
+
 func (rule *ReplaceRule) doReplace(textLine string) (results []*ReplaceResult, err error) {
+	if rule.LiteralMatch {
+		return rule.doLiteralReplace(textLine)
+	}
 	aux := rule.RegexTrap.FindStringSubmatchIndex(textLine)
 	if nil == aux {
 		return
@@ -92,6 +172,8 @@ func (rule *ReplaceRule) doReplace(textLine string) (results []*ReplaceResult, e
 		result := &ReplaceResult{
 			PrefixLiteral: textLine[previousSuffixStart:replaceStart],
 			ReplacedCode:  target.ReplacementCode,
+			Escaper:       target.Escaper,
+			Location:      target.Location,
 		}
 		if targetIndex == targetBoundIndex {
 			result.SuffixLiteral = textLine[suffixStart:]
@@ -108,6 +190,8 @@ type ReplaceResult struct {
 	PrefixLiteral string
 	ReplacedCode  string
 	SuffixLiteral string
+	Escaper       string
+	Location      SourceLocation
 }
 
 func (r *ReplaceResult) isEmpty() bool {
@@ -129,6 +213,8 @@ func (r *ReplaceResult) runReplaceWith(rule *ReplaceRule) (prefixResults []*Repl
 		PrefixLiteral: r.PrefixLiteral,
 		ReplacedCode:  r.ReplacedCode,
 		SuffixLiteral: r.SuffixLiteral,
+		Escaper:       r.Escaper,
+		Location:      r.Location,
 	}
 	if r.PrefixLiteral != "" {
 		if prefixResults, err = rule.doReplace(r.PrefixLiteral); nil != err {
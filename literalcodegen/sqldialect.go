@@ -0,0 +1,333 @@
+package literalcodegen
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Known SQL dialects.
+const (
+	SQLDialectPostgres = "postgres"
+	SQLDialectMySQL    = "mysql"
+	SQLDialectSQLite   = "sqlite"
+)
+
+// LanguageFilterArgSQLDialect is the key of code block argument which tags
+// SQL content with dialect not in known dialects (eg: `sql dialect=mssql`).
+const LanguageFilterArgSQLDialect = "dialect"
+
+// SQLDialectSelectorSymbolSuffix is appended to entry name to form the name
+// of generated dialect selector function.
+const SQLDialectSelectorSymbolSuffix = "ForDialect"
+
+var sqlDialectAliases = map[string]string{
+	"postgres":   SQLDialectPostgres,
+	"postgresql": SQLDialectPostgres,
+	"pg":         SQLDialectPostgres,
+	"mysql":      SQLDialectMySQL,
+	"sqlite":     SQLDialectSQLite,
+	"sqlite3":    SQLDialectSQLite,
+}
+
+var sqlDialectSymbolSuffixes = map[string]string{
+	SQLDialectPostgres: "Postgres",
+	SQLDialectMySQL:    "MySQL",
+	SQLDialectSQLite:   "SQLite",
+}
+
+// canonicalSQLDialect return canonical name of given dialect name. Empty
+// string is returned if name is not a known dialect.
+func canonicalSQLDialect(name string) string {
+	return sqlDialectAliases[strings.ToLower(strings.TrimSpace(name))]
+}
+
+// splitSQLDialectFilterArg extract dialect from code block arguments of SQL
+// content. Known dialect names are taken as flags (eg: `sql postgres`).
+func splitSQLDialectFilterArg(langType string, filterArgs []string) (dialect string, remainArgs []string) {
+	if langType != "sql" {
+		return "", filterArgs
+	}
+	prefix := LanguageFilterArgSQLDialect + "="
+	for _, arg := range filterArgs {
+		if d := canonicalSQLDialect(arg); d != "" {
+			dialect = d
+		} else if strings.HasPrefix(arg, prefix) {
+			dialect = strings.ToLower(strings.TrimPrefix(arg, prefix))
+			if d := canonicalSQLDialect(dialect); d != "" {
+				dialect = d
+			}
+		} else {
+			remainArgs = append(remainArgs, arg)
+		}
+	}
+	return
+}
+
+// sqlDialectSymbolSuffix convert dialect into symbol suffix (eg: `mysql` into `MySQL`).
+func sqlDialectSymbolSuffix(dialect string) string {
+	if suffix, ok := sqlDialectSymbolSuffixes[dialect]; ok {
+		return suffix
+	}
+	return localeSymbolSuffix(dialect)
+}
+
+func (entry *LiteralEntry) setSQLDialectText(v string) {
+	v = strings.ToLower(strings.TrimSpace(v))
+	if d := canonicalSQLDialect(v); d != "" {
+		v = d
+	}
+	entry.SQLDialect = v
+}
+
+func (entry *LiteralEntry) appendSQLDialectsText(v string) {
+	v = strings.ToLower(strings.TrimSpace(v))
+	if d := canonicalSQLDialect(v); d != "" {
+		v = d
+	}
+	entry.SQLDialects = append(entry.SQLDialects, v)
+}
+
+// sqlDialectVariant return variant of entry for given dialect. New variant
+// will be allocated from code if such variant does not exist. Entry takes
+// dialect of first content block if entry has neither content nor dialect.
+func (entry *LiteralEntry) sqlDialectVariant(code *LiteralCode, dialect string) *LiteralEntry {
+	if (entry.SQLDialect == "") && (len(entry.Content) == 0) && (len(entry.SQLDialectVariants) == 0) {
+		entry.SQLDialect = dialect
+	}
+	if dialect == entry.SQLDialect {
+		return entry
+	}
+	for _, variant := range entry.SQLDialectVariants {
+		if variant.SQLDialect == dialect {
+			return variant
+		}
+	}
+	variant := code.NewLiteralConstant()
+	variant.copyOptionsFrom(entry)
+	variant.TitleText = entry.TitleText + " [" + dialect + "]"
+	variant.TitleLocation = entry.TitleLocation
+	variant.LevelDepth = entry.LevelDepth
+	variant.SQLDialect = dialect
+	variant.SQLDialectVariantOf = entry
+	entry.SQLDialectVariants = append(entry.SQLDialectVariants, variant)
+	return variant
+}
+
+// hasSQLDialectVariants checks if entry is the main entry of dialect variants.
+func (entry *LiteralEntry) hasSQLDialectVariants() bool {
+	return (nil == entry.SQLDialectVariantOf) && (len(entry.SQLDialectVariants) > 0)
+}
+
+// IsGenericSQL checks if content of entry is not bound to a dialect. Generic
+// content is used for dialects without variant.
+func (entry *LiteralEntry) IsGenericSQL() bool {
+	return entry.SQLDialect == ""
+}
+
+// SQLDialectsOf return dialects of entry and its variants. Empty result is
+// returned if entry has no dialect variant.
+func (entry *LiteralEntry) SQLDialectsOf() (dialects []string) {
+	if !entry.hasSQLDialectVariants() {
+		return nil
+	}
+	if !entry.IsGenericSQL() {
+		dialects = append(dialects, entry.SQLDialect)
+	}
+	for _, variant := range entry.SQLDialectVariants {
+		dialects = append(dialects, variant.SQLDialect)
+	}
+	return
+}
+
+// IsVariant checks if entry is a locale or SQL dialect variant of another entry.
+func (entry *LiteralEntry) IsVariant() bool {
+	return (nil != entry.LocaleVariantOf) || (nil != entry.SQLDialectVariantOf)
+}
+
+func (entry *LiteralEntry) resolveSQLDialectVariants() (err error) {
+	if (entry.TranslationMode != TranslateAsConst) && (entry.TranslationMode != TranslateAsBuilder) {
+		return fmt.Errorf("%v: SQL dialect variants are only supported by const and builder entries (entry: %v)", entry.TitleLocation, entry.TitleText)
+	}
+	if entry.isLocalized() {
+		return fmt.Errorf("%v: SQL dialect variants cannot be combined with locale variants (entry: %v)", entry.TitleLocation, entry.TitleText)
+	}
+	seen := make(map[string]struct{})
+	if !entry.IsGenericSQL() {
+		seen[entry.SQLDialect] = struct{}{}
+	}
+	for _, variant := range entry.SQLDialectVariants {
+		if variant.IsGenericSQL() {
+			return fmt.Errorf("%v: SQL dialect is required for variant (entry: %v)", variant.TitleLocation, entry.TitleText)
+		}
+		if _, ok := seen[variant.SQLDialect]; ok {
+			return fmt.Errorf("%v: duplicated SQL dialect variant: %s (entry: %v)", variant.TitleLocation, variant.SQLDialect, entry.TitleText)
+		}
+		seen[variant.SQLDialect] = struct{}{}
+		variant.TranslationMode = entry.TranslationMode
+		variant.Parameters = entry.Parameters
+		if nil == variant.BuilderPrepare {
+			variant.BuilderPrepare = entry.BuilderPrepare
+		}
+		if nil == variant.replaceRules {
+			variant.replaceRules = entry.replaceRules
+		}
+		if variant.LanguageType == "" {
+			variant.LanguageType = entry.LanguageType
+			variant.LanguageFilterArgs = entry.LanguageFilterArgs
+		}
+	}
+	return nil
+}
+
+// nameSQLDialectVariants derive names of variants and selector function from
+// name of entry.
+func (entry *LiteralEntry) nameSQLDialectVariants() {
+	if entry.SQLDialectSelectorName == "" {
+		entry.SQLDialectSelectorName = entry.Name + SQLDialectSelectorSymbolSuffix
+	}
+	for _, variant := range entry.SQLDialectVariants {
+		if variant.Name == "" {
+			variant.Name = entry.Name + sqlDialectSymbolSuffix(variant.SQLDialect)
+		}
+	}
+}
+
+// isSQLDialectVariantChild checks if entry is a child heading which only
+// provides content of parent in dialect given by `sql-dialect` option. Dialect
+// taken from tag of code block does not make a child heading a variant.
+func (entry *LiteralEntry) isSQLDialectVariantChild() bool {
+	return entry.sqlDialectFromOption && (entry.SQLDialect != "") && (nil != entry.ParentEntry) && (nil == entry.SQLDialectVariantOf) &&
+		(entry.Name == "") && (len(entry.SQLDialectVariants) == 0)
+}
+
+// ResolveSQLDialectVariants link child entries tagged with `sql-dialect`
+// option to their parents and set up options of dialect variants.
+func (l *LiteralCode) ResolveSQLDialectVariants() (err error) {
+	for _, entry := range l.LiteralConstants {
+		if !entry.isSQLDialectVariantChild() {
+			continue
+		}
+		parent := entry.ParentEntry
+		entry.SQLDialectVariantOf = parent
+		parent.SQLDialectVariants = append(parent.SQLDialectVariants, entry)
+	}
+	for _, entry := range l.LiteralConstants {
+		if !entry.hasSQLDialectVariants() {
+			continue
+		}
+		if err = entry.resolveSQLDialectVariants(); nil != err {
+			return
+		}
+	}
+	return nil
+}
+
+// DeclaredSQLDialects return dialects declared with `sql-dialects` option of
+// heading code. Dialects used by entries are returned if none is declared.
+func (l *LiteralCode) DeclaredSQLDialects() (dialects []string, declared bool) {
+	seen := make(map[string]struct{})
+	for _, entry := range l.HeadingCodes {
+		for _, dialect := range entry.SQLDialects {
+			if _, ok := seen[dialect]; !ok {
+				seen[dialect] = struct{}{}
+				dialects = append(dialects, dialect)
+			}
+		}
+	}
+	if len(dialects) > 0 {
+		return dialects, true
+	}
+	for _, entry := range l.LiteralConstants {
+		for _, dialect := range entry.SQLDialectsOf() {
+			if _, ok := seen[dialect]; !ok {
+				seen[dialect] = struct{}{}
+				dialects = append(dialects, dialect)
+			}
+		}
+	}
+	sort.Strings(dialects)
+	return dialects, false
+}
+
+// MissingSQLDialects return dialects which are declared by document but
+// neither provided by variants of given entry nor covered by generic content.
+func (l *LiteralCode) MissingSQLDialects() (missing map[*LiteralEntry][]string) {
+	dialects, _ := l.DeclaredSQLDialects()
+	missing = make(map[*LiteralEntry][]string)
+	for _, entry := range l.LiteralConstants {
+		if !entry.hasSQLDialectVariants() || entry.IsGenericSQL() {
+			continue
+		}
+		provided := make(map[string]struct{})
+		for _, dialect := range entry.SQLDialectsOf() {
+			provided[dialect] = struct{}{}
+		}
+		var absent []string
+		for _, dialect := range dialects {
+			if _, ok := provided[dialect]; !ok {
+				absent = append(absent, dialect)
+			}
+		}
+		if len(absent) > 0 {
+			missing[entry] = absent
+		}
+	}
+	return
+}
+
+// checkMissingSQLDialects report entries without variants of dialects. It is
+// an error if dialects are declared explicitly.
+func checkMissingSQLDialects(code *LiteralCode) (err error) {
+	_, declared := code.DeclaredSQLDialects()
+	missing := code.MissingSQLDialects()
+	for _, entry := range code.LiteralConstants {
+		absent, ok := missing[entry]
+		if !ok {
+			continue
+		}
+		if declared {
+			return fmt.Errorf("%v: entry [%s] missing SQL dialect variants: %s", entry.TitleLocation, entry.TitleText, strings.Join(absent, ", "))
+		}
+		log.Printf("WARN: %v: entry [%s] missing SQL dialect variants: %s", entry.TitleLocation, entry.TitleText, strings.Join(absent, ", "))
+	}
+	return nil
+}
+
+// generateSQLDialectSelector generate selector function of entry with
+// dialect variants.
+func generateSQLDialectSelector(fp *os.File, entry *LiteralEntry) (err error) {
+	if !entry.hasSQLDialectVariants() {
+		return nil
+	}
+	resultType, argsCode := entry.variantSelectorSignature()
+	var b strings.Builder
+	b.WriteString("// " + entry.SQLDialectSelectorName + " return " + entry.Name + " for given SQL dialect.\n")
+	if entry.IsGenericSQL() {
+		b.WriteString("// Generic variant is returned for dialects without specific variant.\n")
+	} else {
+		b.WriteString("// Result ok is false if variant of given dialect is not available.\n")
+	}
+	b.WriteString("func " + entry.SQLDialectSelectorName + "(" + entry.variantSelectorParameters("dialect string") + ") (result " + resultType + ", ok bool) {\n")
+	b.WriteString("\tswitch dialect {\n")
+	variants := entry.SQLDialectVariants
+	if !entry.IsGenericSQL() {
+		variants = append([]*LiteralEntry{entry}, variants...)
+	}
+	for _, variant := range variants {
+		b.WriteString("\tcase " + strconv.Quote(variant.SQLDialect) + ":\n")
+		b.WriteString("\t\treturn " + variant.Name + argsCode + ", true\n")
+	}
+	b.WriteString("\t}\n")
+	if entry.IsGenericSQL() {
+		b.WriteString("\treturn " + entry.Name + argsCode + ", true\n")
+	} else {
+		b.WriteString("\treturn\n")
+	}
+	b.WriteString("}\n\n")
+	_, err = fp.WriteString(b.String())
+	return
+}
@@ -0,0 +1,103 @@
+package literalcodegen
+
+import (
+	"strings"
+	"testing"
+)
+
+const sqlDialectTestMarkdown = "# Upsert\n\n" +
+	"* `builder`: `sqlUpsertUser`, `table string`\n\n" +
+	"```sql postgres\nINSERT INTO u (id) VALUES ($1) ON CONFLICT DO NOTHING\n```\n\n" +
+	"```sql mysql\nINSERT IGNORE INTO u (id) VALUES (?)\n```\n\n" +
+	"## SQLite\n\n" +
+	"* `sql-dialect`: `sqlite3`\n\n" +
+	"```sql\nINSERT OR IGNORE INTO u (id) VALUES (?)\n```\n\n" +
+	"# Count\n\n" +
+	"* `const`: `sqlCount`\n\n" +
+	"```sql\nSELECT COUNT(*) FROM u\n```\n\n" +
+	"```sql dialect=MSSQL\nSELECT COUNT_BIG(*) FROM u\n```\n\n" +
+	"# Queries\n\n" +
+	"## Fetch\n\n" +
+	"* `const`: `sqlFetch`\n\n" +
+	"```sql pg\nSELECT * FROM u LIMIT 1\n```\n\n" +
+	"```sql mysql\nSELECT * FROM u LIMIT 1\n```\n\n" +
+	"```sql sqlite\nSELECT * FROM u LIMIT 1\n```\n\n" +
+	"## Tagged Child\n\n" +
+	"```sql postgres\nSELECT 1\n```\n"
+
+func TestResolveSQLDialectVariants(t *testing.T) {
+	code, err := parseMarkdownText(t, sqlDialectTestMarkdown)
+	if nil != err {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	nameVariantEntries(code.LiteralConstants)
+	tests := []struct {
+		titleText    string
+		dialect      string
+		dialects     []string
+		variantNames []string
+		variantOf    string
+	}{
+		{"Upsert", "postgres", []string{"postgres", "mysql", "sqlite"}, []string{"sqlUpsertUserMySQL", "sqlUpsertUserSQLite"}, ""},
+		{"SQLite", "sqlite", nil, nil, "Upsert"},
+		{"Count", "", []string{"mssql"}, []string{"sqlCountMssql"}, ""},
+		{"Fetch", "postgres", []string{"postgres", "mysql", "sqlite"}, []string{"sqlFetchMySQL", "sqlFetchSQLite"}, ""},
+		{"Tagged Child", "postgres", nil, nil, ""},
+	}
+	for _, tt := range tests {
+		entry := findLiteralEntry(code, tt.titleText)
+		if nil == entry {
+			t.Errorf("%s: entry not found", tt.titleText)
+			continue
+		}
+		if entry.SQLDialect != tt.dialect {
+			t.Errorf("%s: expecting dialect %q but having %q", tt.titleText, tt.dialect, entry.SQLDialect)
+		}
+		if dialects := strings.Join(entry.SQLDialectsOf(), ","); dialects != strings.Join(tt.dialects, ",") {
+			t.Errorf("%s: expecting dialects %v but having %v", tt.titleText, tt.dialects, entry.SQLDialectsOf())
+		}
+		var variantNames []string
+		for _, variant := range entry.SQLDialectVariants {
+			variantNames = append(variantNames, variant.Name)
+		}
+		if strings.Join(variantNames, ",") != strings.Join(tt.variantNames, ",") {
+			t.Errorf("%s: expecting variant names %v but having %v", tt.titleText, tt.variantNames, variantNames)
+		}
+		variantOf := ""
+		if nil != entry.SQLDialectVariantOf {
+			variantOf = entry.SQLDialectVariantOf.TitleText
+		}
+		if variantOf != tt.variantOf {
+			t.Errorf("%s: expecting variant of %q but having %q", tt.titleText, tt.variantOf, variantOf)
+		}
+	}
+	if name := findLiteralEntry(code, "Upsert").SQLDialectSelectorName; name != "sqlUpsertUser"+SQLDialectSelectorSymbolSuffix {
+		t.Errorf("unexpected name of selector function: %q", name)
+	}
+}
+
+func TestResolveSQLDialectVariantsError(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{
+			name: "missing declared dialect",
+			text: "# Heading Code\n\n* `sql-dialects`: `postgres`, `mysql`\n\n```go\npackage x\n```\n\n" +
+				"# A\n\n* `const`: `a`\n\n```sql postgres\nSELECT 1\n```\n\n```sql sqlite\nSELECT 1\n```\n",
+		},
+		{
+			name: "duplicated dialect",
+			text: "# A\n\n* `const`: `a`\n\n```sql postgres\nSELECT 1\n```\n\n## B\n\n* `sql-dialect`: `pg`\n\n```sql\nSELECT 2\n```\n",
+		},
+		{
+			name: "combined with locale variants",
+			text: "# A\n\n* `const`: `a`\n* `locale`: `en`\n\n```sql postgres\nSELECT 1\n```\n\n```sql mysql\nSELECT 2\n```\n\n## B\n\n* `locale`: `de`\n\n```sql\nSELECT 3\n```\n",
+		},
+	}
+	for _, tt := range tests {
+		if _, err := parseMarkdownText(t, tt.text); nil == err {
+			t.Errorf("%s: expecting error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package literalcodegen
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	RegisterLanguageFilter("sql", &sqlLanguageFilter{})
+}
+
+const (
+	sqlOutputCompact = "compact"
+	sqlOutputPretty  = "pretty"
+)
+
+type sqlFilterOptions struct {
+	removeComments    bool
+	backslashEscape   bool
+	outputMode        string
+	placeholderStyle  string
+	placeholderSource string
+	splitStatements   bool
+}
+
+func parseSQLFilterArgs(filterArgs LanguageFilterArgs) (opts *sqlFilterOptions, err error) {
+	opts = &sqlFilterOptions{
+		removeComments:    !filterArgs.Has("keep-comment"),
+		backslashEscape:   filterArgs.Has("backslash-escape"),
+		outputMode:        filterArgs.Choice(sqlOutputCompact, sqlOutputCompact, sqlOutputPretty),
+		placeholderStyle:  filterArgs.ValueOr("placeholder", ""),
+		placeholderSource: filterArgs.ValueOr("placeholder-source", ""),
+		splitStatements:   filterArgs.Has("split-statements"),
+	}
+	if (opts.placeholderStyle != "") && !isValidSQLPlaceholderStyle(opts.placeholderStyle) {
+		return nil, fmt.Errorf("unknown SQL placeholder style: %s", opts.placeholderStyle)
+	}
+	if opts.placeholderSource != "" {
+		if !isValidSQLPlaceholderStyle(opts.placeholderSource) {
+			return nil, fmt.Errorf("unknown SQL placeholder source style: %s", opts.placeholderSource)
+		}
+		if opts.placeholderStyle == "" {
+			return nil, fmt.Errorf("placeholder-source requires placeholder argument")
+		}
+	}
+	return opts, nil
+}
+
+func trimTailHorizontalSpace(v string) string {
+	return strings.TrimRight(v, " \t\r\f\v")
+}
+
+func isSQLLineStart(output []sqlToken) bool {
+	lastIndex := len(output) - 1
+	if lastIndex < 0 {
+		return true
+	}
+	last := output[lastIndex]
+	if last.Type != sqlTokenWhitespace {
+		return false
+	}
+	return (lastIndex == 0) || strings.Contains(last.Text, "\n")
+}
+
+func isSQLLineEnd(tokens []sqlToken, nextIndex int) bool {
+	if nextIndex >= len(tokens) {
+		return true
+	}
+	next := tokens[nextIndex]
+	return (next.Type == sqlTokenWhitespace) && strings.Contains(next.Text, "\n")
+}
+
+func dropTailHorizontalSpace(output []sqlToken) []sqlToken {
+	lastIndex := len(output) - 1
+	if (lastIndex < 0) || (output[lastIndex].Type != sqlTokenWhitespace) {
+		return output
+	}
+	if text := trimTailHorizontalSpace(output[lastIndex].Text); text == "" {
+		return output[:lastIndex]
+	} else {
+		output[lastIndex].Text = text
+	}
+	return output
+}
+
+// stripSQLComments remove comment tokens. Lines which only contain comments
+// are removed as well. Comment between two tokens is replaced with a space to
+// keep the tokens separated.
+func stripSQLComments(tokens []sqlToken) (output []sqlToken) {
+	skipNewLine := false
+	for idx, tok := range tokens {
+		if !tok.isComment() {
+			if skipNewLine && (tok.Type == sqlTokenWhitespace) {
+				if nlIdx := strings.IndexByte(tok.Text, '\n'); nlIdx >= 0 {
+					tok.Text = tok.Text[nlIdx+1:]
+				}
+				skipNewLine = false
+				if tok.Text == "" {
+					continue
+				}
+			}
+			skipNewLine = false
+			output = append(output, tok)
+			continue
+		}
+		lineStart := isSQLLineStart(output)
+		lineEnd := isSQLLineEnd(tokens, idx+1)
+		output = dropTailHorizontalSpace(output)
+		if lineStart && lineEnd {
+			skipNewLine = true
+			continue
+		}
+		if (!lineEnd) && (len(output) > 0) && (tokens[idx+1].Type != sqlTokenWhitespace) {
+			output = append(output, sqlToken{
+				Type: sqlTokenWhitespace,
+				Text: " ",
+			})
+		}
+	}
+	return
+}
+
+func isSQLTailToken(tok *sqlToken) bool {
+	return (tok.Type == sqlTokenWhitespace) || ((tok.Type == sqlTokenPunctuation) && (tok.Text == ";"))
+}
+
+// trimSQLTailTokens remove tailing white spaces and semicolons.
+func trimSQLTailTokens(tokens []sqlToken) []sqlToken {
+	for len(tokens) > 0 {
+		if !isSQLTailToken(&tokens[len(tokens)-1]) {
+			break
+		}
+		tokens = tokens[:len(tokens)-1]
+	}
+	return tokens
+}
+
+// filterSQLTokens lex and filter SQL content. Content is split into
+// statements first when `split-statements` is given, otherwise the whole
+// content is the only statement. Placeholders are numbered per statement
+// since each statement is executed on its own.
+func filterSQLTokens(codeContent []string, filterArgs LanguageFilterArgs) (opts *sqlFilterOptions, statements [][]sqlToken, paramNames [][]string, newLineMode contentNewLineMode, err error) {
+	if opts, err = parseSQLFilterArgs(filterArgs); nil != err {
+		return
+	}
+	var text string
+	text, newLineMode = joinContentLines(codeContent)
+	tokens, err := lexSQL(text, opts.backslashEscape)
+	if nil != err {
+		return
+	}
+	if opts.removeComments {
+		tokens = stripSQLComments(tokens)
+	}
+	tokens = trimSQLTailTokens(tokens)
+	if opts.splitStatements {
+		statements = splitSQLStatements(tokens)
+	} else {
+		statements = [][]sqlToken{tokens}
+	}
+	if opts.placeholderStyle == "" {
+		return
+	}
+	for _, statement := range statements {
+		names, err := convertSQLPlaceholders(statement, opts.placeholderStyle, opts.placeholderSource)
+		if nil != err {
+			return nil, nil, nil, newLineMode, err
+		}
+		paramNames = append(paramNames, names)
+	}
+	return
+}
+
+type sqlLanguageFilter struct{}
+
+func (opts *sqlFilterOptions) joinTokens(tokens []sqlToken) []string {
+	switch opts.outputMode {
+	case sqlOutputPretty:
+		return joinSQLTokensPretty(tokens)
+	}
+	return joinSQLTokensCompact(tokens)
+}
+
+func (f *sqlLanguageFilter) FilterContent(codeContent []string, filterArgs LanguageFilterArgs) (result []string, err error) {
+	opts, statements, _, newLineMode, err := filterSQLTokens(codeContent, filterArgs)
+	if nil != err {
+		return
+	}
+	for idx, statement := range statements {
+		lines := opts.joinTokens(statement)
+		if (idx < len(statements)-1) && (len(lines) > 0) {
+			lines[len(lines)-1] = lines[len(lines)-1] + ";"
+		}
+		result = append(result, lines...)
+	}
+	return newLineMode.restore(result), nil
+}
+
+// WillSplitContent checks if `split-statements` is given.
+func (f *sqlLanguageFilter) WillSplitContent(filterArgs LanguageFilterArgs) bool {
+	return filterArgs.Has("split-statements")
+}
+
+// SplitContent split content into SQL statements.
+func (f *sqlLanguageFilter) SplitContent(codeContent []string, filterArgs LanguageFilterArgs) (pieces [][]string, err error) {
+	opts, statements, _, newLineMode, err := filterSQLTokens(codeContent, filterArgs)
+	if nil != err {
+		return
+	}
+	if !opts.splitStatements {
+		return nil, nil
+	}
+	for _, statement := range statements {
+		pieces = append(pieces, newLineMode.restore(opts.joinTokens(statement)))
+	}
+	return pieces, nil
+}
+
+func quoteSQLParamNames(paramNames []string) string {
+	quotedNames := make([]string, 0, len(paramNames))
+	for _, name := range paramNames {
+		quotedNames = append(quotedNames, strconv.Quote(name))
+	}
+	return "{" + strings.Join(quotedNames, ", ") + "}"
+}
+
+// GenerateSupplementCode generate ordered parameter name list when named
+// placeholders are converted. One list per statement is generated when
+// content is split into statements.
+func (f *sqlLanguageFilter) GenerateSupplementCode(fp *os.File, entry *LiteralEntry) (err error) {
+	opts, _, paramNames, _, err := filterSQLTokens(entry.Content, LanguageFilterArgs(entry.LanguageFilterArgs))
+	if nil != err {
+		return
+	}
+	namesCount := 0
+	for _, names := range paramNames {
+		namesCount += len(names)
+	}
+	if (opts.placeholderStyle == "") || (namesCount == 0) {
+		return nil
+	}
+	if !opts.splitStatements {
+		_, err = fp.WriteString("var " + entry.Name + SQLParamNamesSymbolSuffix + " = []string" + quoteSQLParamNames(paramNames[0]) + "\n\n")
+		return
+	}
+	statementNames := make([]string, 0, len(paramNames))
+	for _, names := range paramNames {
+		statementNames = append(statementNames, quoteSQLParamNames(names))
+	}
+	_, err = fp.WriteString("var " + entry.Name + SQLParamNamesSymbolSuffix + " = [][]string{" + strings.Join(statementNames, ", ") + "}\n\n")
+	return
+}
@@ -0,0 +1,106 @@
+package literalcodegen
+
+import (
+	"strings"
+)
+
+// sqlNoSpaceAfterPunctuations are punctuations which do not need white
+// space to separate from the following token.
+const sqlNoSpaceAfterPunctuations = "(.,"
+
+// sqlNoSpaceBeforePunctuations are punctuations which do not need white
+// space to separate from the preceding token.
+const sqlNoSpaceBeforePunctuations = ").,;"
+
+func isSQLPunctuationOf(tok *sqlToken, punctuations string) bool {
+	return (tok.Type == sqlTokenPunctuation) && (len(tok.Text) == 1) && strings.Contains(punctuations, tok.Text)
+}
+
+// sqlSeparator return the minimal separation which is required between
+// two tokens which are separated with white spaces in source. White space
+// is only removed next to brackets, dots, commas and semicolons, where
+// removing it never joins two tokens into one.
+func sqlSeparator(prev, next *sqlToken) string {
+	if prev.Type == sqlTokenLineComment {
+		return "\n"
+	}
+	if isSQLPunctuationOf(prev, sqlNoSpaceAfterPunctuations) || isSQLPunctuationOf(next, sqlNoSpaceBeforePunctuations) {
+		return ""
+	}
+	return " "
+}
+
+// joinSQLTokensCompact collapse white spaces between tokens into minimal
+// separation. Lines are kept in result for readability of generated code but
+// joining result lines without new line gives the compacted SQL.
+func joinSQLTokensCompact(tokens []sqlToken) (lines []string) {
+	var b strings.Builder
+	var prev *sqlToken
+	pendingSpace := false
+	pendingNewLine := false
+	for idx := range tokens {
+		tok := &tokens[idx]
+		if tok.Type == sqlTokenWhitespace {
+			pendingSpace = true
+			pendingNewLine = pendingNewLine || strings.Contains(tok.Text, "\n")
+			continue
+		}
+		if nil != prev {
+			var sep string
+			if pendingSpace {
+				sep = sqlSeparator(prev, tok)
+			}
+			if sep == "\n" {
+				lines = append(lines, b.String()+sep)
+				b.Reset()
+			} else if pendingNewLine {
+				lines = append(lines, b.String())
+				b.Reset()
+				b.WriteString(sep)
+			} else {
+				b.WriteString(sep)
+			}
+		}
+		b.WriteString(tok.Text)
+		prev = tok
+		pendingSpace = false
+		pendingNewLine = false
+	}
+	if b.Len() > 0 {
+		lines = append(lines, b.String())
+	}
+	return
+}
+
+// joinSQLTokensPretty keep line structure and indent of SQL. White spaces
+// within line are collapsed into single space and empty lines are removed.
+// Each line except the last one is ended with new line character.
+func joinSQLTokensPretty(tokens []sqlToken) (lines []string) {
+	var b strings.Builder
+	for idx := range tokens {
+		tok := &tokens[idx]
+		if tok.Type != sqlTokenWhitespace {
+			b.WriteString(tok.Text)
+			continue
+		}
+		if b.Len() == 0 {
+			if nlIdx := strings.LastIndexByte(tok.Text, '\n'); nlIdx >= 0 {
+				b.WriteString(tok.Text[nlIdx+1:])
+			} else {
+				b.WriteString(tok.Text)
+			}
+			continue
+		}
+		if nlIdx := strings.LastIndexByte(tok.Text, '\n'); nlIdx >= 0 {
+			lines = append(lines, b.String()+"\n")
+			b.Reset()
+			b.WriteString(tok.Text[nlIdx+1:])
+		} else if idx+1 < len(tokens) {
+			b.WriteString(" ")
+		}
+	}
+	if b.Len() > 0 {
+		lines = append(lines, b.String())
+	}
+	return
+}
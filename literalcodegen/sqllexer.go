@@ -0,0 +1,287 @@
+package literalcodegen
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type sqlTokenType int
+
+const (
+	sqlTokenWhitespace sqlTokenType = iota
+	sqlTokenWord
+	sqlTokenNumber
+	sqlTokenQuoted
+	sqlTokenLineComment
+	sqlTokenBlockComment
+	sqlTokenPunctuation
+	sqlTokenPlaceholder
+)
+
+type sqlToken struct {
+	Type sqlTokenType
+	Text string
+}
+
+func (tok *sqlToken) isComment() bool {
+	return (tok.Type == sqlTokenLineComment) || (tok.Type == sqlTokenBlockComment)
+}
+
+// sqlLexer splits SQL text into tokens. The lexer only recognizes the
+// lexical structure which is required for filtering: quoted strings and
+// identifiers, dollar-quoted strings, comments, words, numbers and
+// punctuations.
+type sqlLexer struct {
+	text            string
+	pos             int
+	backslashEscape bool
+	tokens          []sqlToken
+}
+
+func newSQLLexer(text string, backslashEscape bool) *sqlLexer {
+	return &sqlLexer{
+		text:            text,
+		backslashEscape: backslashEscape,
+	}
+}
+
+func (lexer *sqlLexer) errorf(format string, a ...interface{}) error {
+	prefix := lexer.text[:lexer.pos]
+	return &ContentPositionError{
+		LineIndex: strings.Count(prefix, "\n"),
+		Column:    len(prefix) - strings.LastIndexByte(prefix, '\n'),
+		Err:       fmt.Errorf(format, a...),
+	}
+}
+
+func (lexer *sqlLexer) emit(tokenType sqlTokenType, start int) {
+	lexer.tokens = append(lexer.tokens, sqlToken{
+		Type: tokenType,
+		Text: lexer.text[start:lexer.pos],
+	})
+}
+
+func (lexer *sqlLexer) peek(offset int) byte {
+	if idx := lexer.pos + offset; idx < len(lexer.text) {
+		return lexer.text[idx]
+	}
+	return 0
+}
+
+func isSQLWordRune(r rune) bool {
+	return (r == '_') || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func (lexer *sqlLexer) scanWhile(accept func(r rune) bool) {
+	for lexer.pos < len(lexer.text) {
+		r, size := utf8.DecodeRuneInString(lexer.text[lexer.pos:])
+		if !accept(r) {
+			return
+		}
+		lexer.pos += size
+	}
+}
+
+func (lexer *sqlLexer) scanQuoted(quote byte) (err error) {
+	start := lexer.pos
+	lexer.pos++
+	for lexer.pos < len(lexer.text) {
+		ch := lexer.text[lexer.pos]
+		if (ch == '\\') && lexer.backslashEscape && (quote != '`') {
+			lexer.pos += 2
+			continue
+		}
+		lexer.pos++
+		if ch != quote {
+			continue
+		}
+		if lexer.peek(0) == quote {
+			lexer.pos++
+			continue
+		}
+		lexer.emit(sqlTokenQuoted, start)
+		return nil
+	}
+	lexer.pos = start
+	return lexer.errorf("unterminated quoted text started with %c", quote)
+}
+
+func (lexer *sqlLexer) dollarQuoteTag() string {
+	if lexer.peek(0) != '$' {
+		return ""
+	}
+	idx := lexer.pos + 1
+	for idx < len(lexer.text) {
+		ch := lexer.text[idx]
+		if ch == '$' {
+			return lexer.text[lexer.pos : idx+1]
+		}
+		isTagCh := (ch == '_') || ((ch >= 'a') && (ch <= 'z')) || ((ch >= 'A') && (ch <= 'Z')) || ((ch >= '0') && (ch <= '9') && (idx > lexer.pos+1))
+		if !isTagCh {
+			return ""
+		}
+		idx++
+	}
+	return ""
+}
+
+func (lexer *sqlLexer) scanDollarQuoted(tag string) (err error) {
+	start := lexer.pos
+	idx := strings.Index(lexer.text[start+len(tag):], tag)
+	if idx < 0 {
+		return lexer.errorf("unterminated dollar-quoted text started with %s", tag)
+	}
+	lexer.pos = start + len(tag) + idx + len(tag)
+	lexer.emit(sqlTokenQuoted, start)
+	return nil
+}
+
+func (lexer *sqlLexer) scanLineComment() {
+	start := lexer.pos
+	if idx := strings.IndexByte(lexer.text[start:], '\n'); idx < 0 {
+		lexer.pos = len(lexer.text)
+	} else {
+		lexer.pos = start + idx
+	}
+	lexer.emit(sqlTokenLineComment, start)
+}
+
+func (lexer *sqlLexer) scanBlockComment() (err error) {
+	start := lexer.pos
+	depth := 0
+	for lexer.pos < len(lexer.text) {
+		if (lexer.peek(0) == '/') && (lexer.peek(1) == '*') {
+			depth++
+			lexer.pos += 2
+		} else if (lexer.peek(0) == '*') && (lexer.peek(1) == '/') {
+			depth--
+			lexer.pos += 2
+			if depth == 0 {
+				lexer.emit(sqlTokenBlockComment, start)
+				return nil
+			}
+		} else {
+			lexer.pos++
+		}
+	}
+	lexer.pos = start
+	return lexer.errorf("unterminated block comment")
+}
+
+func (lexer *sqlLexer) scanNumber() {
+	start := lexer.pos
+	lexer.scanWhile(unicode.IsDigit)
+	if (lexer.peek(0) == '.') && (lexer.peek(1) >= '0') && (lexer.peek(1) <= '9') {
+		lexer.pos++
+		lexer.scanWhile(unicode.IsDigit)
+	}
+	if ch := lexer.peek(0); (ch == 'e') || (ch == 'E') {
+		offset := 1
+		if sign := lexer.peek(1); (sign == '+') || (sign == '-') {
+			offset = 2
+		}
+		if digit := lexer.peek(offset); (digit >= '0') && (digit <= '9') {
+			lexer.pos += offset
+			lexer.scanWhile(unicode.IsDigit)
+		}
+	}
+	lexer.scanWhile(isSQLWordRune)
+	lexer.emit(sqlTokenNumber, start)
+}
+
+func isSQLIdentifierStartByte(ch byte) bool {
+	return (ch == '_') || ((ch >= 'a') && (ch <= 'z')) || ((ch >= 'A') && (ch <= 'Z'))
+}
+
+// scanPlaceholder scan bind parameter placeholder in form of `?`, `$N`,
+// `:name` or `@name`. Return false if text at current position is not
+// a placeholder.
+func (lexer *sqlLexer) scanPlaceholder() bool {
+	start := lexer.pos
+	switch ch := lexer.peek(0); ch {
+	case '?':
+		lexer.pos++
+	case '$':
+		if next := lexer.peek(1); (next < '0') || (next > '9') {
+			return false
+		}
+		lexer.pos++
+		lexer.scanWhile(unicode.IsDigit)
+	case ':', '@':
+		if !isSQLIdentifierStartByte(lexer.peek(1)) {
+			return false
+		}
+		if (start > 0) && ((lexer.text[start-1] == ch) || isSQLWordRune(rune(lexer.text[start-1]))) {
+			return false
+		}
+		lexer.pos++
+		lexer.scanWhile(isSQLWordRune)
+	default:
+		return false
+	}
+	lexer.emit(sqlTokenPlaceholder, start)
+	return true
+}
+
+func (lexer *sqlLexer) scanToken() (err error) {
+	start := lexer.pos
+	ch := lexer.text[lexer.pos]
+	switch {
+	case (ch == '\'') || (ch == '"') || (ch == '`'):
+		return lexer.scanQuoted(ch)
+	case (ch == '-') && (lexer.peek(1) == '-'):
+		lexer.scanLineComment()
+		return nil
+	case (ch == '/') && (lexer.peek(1) == '*'):
+		return lexer.scanBlockComment()
+	case ch == '$':
+		if tag := lexer.dollarQuoteTag(); tag != "" {
+			return lexer.scanDollarQuoted(tag)
+		}
+	case (ch >= '0') && (ch <= '9'):
+		lexer.scanNumber()
+		return nil
+	case (ch == ':') && (lexer.peek(1) == ':'):
+		lexer.pos += 2
+		lexer.emit(sqlTokenPunctuation, start)
+		return nil
+	}
+	if lexer.scanPlaceholder() {
+		return nil
+	}
+	r, size := utf8.DecodeRuneInString(lexer.text[lexer.pos:])
+	if unicode.IsSpace(r) {
+		lexer.scanWhile(unicode.IsSpace)
+		lexer.emit(sqlTokenWhitespace, start)
+	} else if isSQLWordRune(r) {
+		lexer.scanWhile(isSQLWordRune)
+		lexer.emit(sqlTokenWord, start)
+	} else {
+		lexer.pos += size
+		lexer.emit(sqlTokenPunctuation, start)
+	}
+	return nil
+}
+
+func (lexer *sqlLexer) run() (tokens []sqlToken, err error) {
+	for lexer.pos < len(lexer.text) {
+		if err = lexer.scanToken(); nil != err {
+			return
+		}
+	}
+	return lexer.tokens, nil
+}
+
+func lexSQL(text string, backslashEscape bool) (tokens []sqlToken, err error) {
+	return newSQLLexer(text, backslashEscape).run()
+}
+
+func joinSQLTokens(tokens []sqlToken) string {
+	var b strings.Builder
+	for _, tok := range tokens {
+		b.WriteString(tok.Text)
+	}
+	return b.String()
+}
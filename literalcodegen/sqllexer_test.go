@@ -0,0 +1,139 @@
+package literalcodegen
+
+import (
+	"testing"
+)
+
+func TestLexSQL(t *testing.T) {
+	tests := []struct {
+		name            string
+		text            string
+		backslashEscape bool
+		expect          []sqlToken
+	}{
+		{
+			name:   "empty",
+			text:   "",
+			expect: nil,
+		},
+		{
+			name: "words and punctuations",
+			text: "SELECT a, b FROM t;",
+			expect: []sqlToken{
+				{sqlTokenWord, "SELECT"},
+				{sqlTokenWhitespace, " "},
+				{sqlTokenWord, "a"},
+				{sqlTokenPunctuation, ","},
+				{sqlTokenWhitespace, " "},
+				{sqlTokenWord, "b"},
+				{sqlTokenWhitespace, " "},
+				{sqlTokenWord, "FROM"},
+				{sqlTokenWhitespace, " "},
+				{sqlTokenWord, "t"},
+				{sqlTokenPunctuation, ";"},
+			},
+		},
+		{
+			name: "quoted text with semicolon and doubled quote",
+			text: "'a;''b' \"c;d\" `e`",
+			expect: []sqlToken{
+				{sqlTokenQuoted, "'a;''b'"},
+				{sqlTokenWhitespace, " "},
+				{sqlTokenQuoted, "\"c;d\""},
+				{sqlTokenWhitespace, " "},
+				{sqlTokenQuoted, "`e`"},
+			},
+		},
+		{
+			name:            "backslash escape",
+			text:            `'a\'b'`,
+			backslashEscape: true,
+			expect: []sqlToken{
+				{sqlTokenQuoted, `'a\'b'`},
+			},
+		},
+		{
+			name: "dollar quoted",
+			text: "$$ a; $1 $$ $fn$ b $fn$",
+			expect: []sqlToken{
+				{sqlTokenQuoted, "$$ a; $1 $$"},
+				{sqlTokenWhitespace, " "},
+				{sqlTokenQuoted, "$fn$ b $fn$"},
+			},
+		},
+		{
+			name: "comments",
+			text: "a -- c1;\n/* c2 /* c3 */ ; */b",
+			expect: []sqlToken{
+				{sqlTokenWord, "a"},
+				{sqlTokenWhitespace, " "},
+				{sqlTokenLineComment, "-- c1;"},
+				{sqlTokenWhitespace, "\n"},
+				{sqlTokenBlockComment, "/* c2 /* c3 */ ; */"},
+				{sqlTokenWord, "b"},
+			},
+		},
+		{
+			name: "placeholders",
+			text: "? $2 :name @id",
+			expect: []sqlToken{
+				{sqlTokenPlaceholder, "?"},
+				{sqlTokenWhitespace, " "},
+				{sqlTokenPlaceholder, "$2"},
+				{sqlTokenWhitespace, " "},
+				{sqlTokenPlaceholder, ":name"},
+				{sqlTokenWhitespace, " "},
+				{sqlTokenPlaceholder, "@id"},
+			},
+		},
+		{
+			name: "type cast and variables are not placeholders",
+			text: "a::int @@v",
+			expect: []sqlToken{
+				{sqlTokenWord, "a"},
+				{sqlTokenPunctuation, "::"},
+				{sqlTokenWord, "int"},
+				{sqlTokenWhitespace, " "},
+				{sqlTokenPunctuation, "@"},
+				{sqlTokenPunctuation, "@"},
+				{sqlTokenWord, "v"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tokens, err := lexSQL(tt.text, tt.backslashEscape)
+		if nil != err {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if len(tokens) != len(tt.expect) {
+			t.Errorf("%s: expecting %d tokens but having %d: %#v", tt.name, len(tt.expect), len(tokens), tokens)
+			continue
+		}
+		for idx, tok := range tokens {
+			if tok != tt.expect[idx] {
+				t.Errorf("%s: token %d: expecting %#v but having %#v", tt.name, idx, tt.expect[idx], tok)
+			}
+		}
+		if joined := joinSQLTokens(tokens); joined != tt.text {
+			t.Errorf("%s: joined text not identical to input: %q", tt.name, joined)
+		}
+	}
+}
+
+func TestLexSQLError(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"unterminated quoted text", "SELECT 'abc"},
+		{"unterminated identifier", "SELECT \"abc"},
+		{"unterminated dollar quoted text", "SELECT $tag$ abc"},
+		{"unterminated block comment", "SELECT /* /* */ 1"},
+	}
+	for _, tt := range tests {
+		if _, err := lexSQL(tt.text, false); nil == err {
+			t.Errorf("%s: expecting error", tt.name)
+		}
+	}
+}
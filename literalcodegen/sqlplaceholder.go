@@ -0,0 +1,161 @@
+package literalcodegen
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Styles of SQL bind parameter placeholder.
+const (
+	SQLPlaceholderQuestion = "question"
+	SQLPlaceholderDollar   = "dollar"
+	SQLPlaceholderColon    = "colon"
+	SQLPlaceholderAt       = "at"
+)
+
+// SQLParamNamesSymbolSuffix is appended to entry name to form the symbol of
+// generated parameter name list.
+const SQLParamNamesSymbolSuffix = "ParamNames"
+
+func sqlPlaceholderStyleOf(text string) string {
+	switch text[0] {
+	case '?':
+		return SQLPlaceholderQuestion
+	case '$':
+		return SQLPlaceholderDollar
+	case ':':
+		return SQLPlaceholderColon
+	case '@':
+		return SQLPlaceholderAt
+	}
+	return ""
+}
+
+func isValidSQLPlaceholderStyle(style string) bool {
+	switch style {
+	case SQLPlaceholderQuestion, SQLPlaceholderDollar, SQLPlaceholderColon, SQLPlaceholderAt:
+		return true
+	}
+	return false
+}
+
+func isNamedSQLPlaceholderStyle(style string) bool {
+	return (style == SQLPlaceholderColon) || (style == SQLPlaceholderAt)
+}
+
+// sqlPlaceholderConversion converts placeholders of one style into another.
+type sqlPlaceholderConversion struct {
+	targetStyle string
+	sourceStyle string
+
+	paramNames   []string
+	nameIndexes  map[string]int
+	nextPosition int
+}
+
+func newSQLPlaceholderConversion(targetStyle, sourceStyle string) *sqlPlaceholderConversion {
+	return &sqlPlaceholderConversion{
+		targetStyle: targetStyle,
+		sourceStyle: sourceStyle,
+		nameIndexes: make(map[string]int),
+	}
+}
+
+func (conv *sqlPlaceholderConversion) checkSourceStyle(text string) (err error) {
+	style := sqlPlaceholderStyleOf(text)
+	if conv.sourceStyle == "" {
+		conv.sourceStyle = style
+		return nil
+	}
+	if conv.sourceStyle != style {
+		return fmt.Errorf("mixed placeholder styles in SQL: %s and %s (%s)", conv.sourceStyle, style, text)
+	}
+	return nil
+}
+
+// positionOf return 1-based position of given placeholder for positional target styles.
+func (conv *sqlPlaceholderConversion) positionOf(text string) (position int, err error) {
+	switch conv.sourceStyle {
+	case SQLPlaceholderQuestion:
+		conv.nextPosition++
+		return conv.nextPosition, nil
+	case SQLPlaceholderDollar:
+		v, err := strconv.ParseInt(text[1:], 10, 31)
+		if nil != err {
+			return 0, err
+		}
+		return int(v), nil
+	}
+	name := text[1:]
+	if idx, ok := conv.nameIndexes[name]; ok {
+		return idx + 1, nil
+	}
+	conv.paramNames = append(conv.paramNames, name)
+	conv.nameIndexes[name] = len(conv.paramNames) - 1
+	return len(conv.paramNames), nil
+}
+
+func (conv *sqlPlaceholderConversion) convert(text string) (result string, err error) {
+	if err = conv.checkSourceStyle(text); nil != err {
+		return
+	}
+	if conv.sourceStyle == conv.targetStyle {
+		if isNamedSQLPlaceholderStyle(conv.sourceStyle) {
+			conv.positionOf(text)
+		}
+		return text, nil
+	}
+	switch conv.targetStyle {
+	case SQLPlaceholderQuestion:
+		if isNamedSQLPlaceholderStyle(conv.sourceStyle) {
+			conv.paramNames = append(conv.paramNames, text[1:])
+			return "?", nil
+		}
+		position, err := conv.positionOf(text)
+		if nil != err {
+			return "", err
+		}
+		conv.nextPosition++
+		if (conv.sourceStyle == SQLPlaceholderDollar) && (position != conv.nextPosition) {
+			return "", fmt.Errorf("cannot convert %s to positional placeholder: expecting $%d", text, conv.nextPosition)
+		}
+		return "?", nil
+	case SQLPlaceholderDollar:
+		position, err := conv.positionOf(text)
+		if nil != err {
+			return "", err
+		}
+		return "$" + strconv.FormatInt(int64(position), 10), nil
+	case SQLPlaceholderColon, SQLPlaceholderAt:
+		if !isNamedSQLPlaceholderStyle(conv.sourceStyle) {
+			return "", fmt.Errorf("cannot convert positional placeholder to named placeholder: %s", text)
+		}
+		conv.positionOf(text)
+		if conv.targetStyle == SQLPlaceholderColon {
+			return ":" + text[1:], nil
+		}
+		return "@" + text[1:], nil
+	}
+	return "", fmt.Errorf("unknown placeholder style: %s", conv.targetStyle)
+}
+
+// convertSQLPlaceholders rewrite placeholder tokens into given target style.
+// Names of parameters are returned in order of binding if placeholders in
+// source are named. When source style is given, tokens of other styles
+// (eg: MySQL `@variable` or PostgreSQL `?` operator) are kept as is.
+func convertSQLPlaceholders(tokens []sqlToken, targetStyle, sourceStyle string) (paramNames []string, err error) {
+	conv := newSQLPlaceholderConversion(targetStyle, sourceStyle)
+	for idx := range tokens {
+		tok := &tokens[idx]
+		if tok.Type != sqlTokenPlaceholder {
+			continue
+		}
+		if (sourceStyle != "") && (sqlPlaceholderStyleOf(tok.Text) != sourceStyle) {
+			continue
+		}
+		if tok.Text, err = conv.convert(tok.Text); nil != err {
+			return
+		}
+	}
+	return conv.paramNames, nil
+}
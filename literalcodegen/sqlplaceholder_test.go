@@ -0,0 +1,134 @@
+package literalcodegen
+
+import (
+	"testing"
+)
+
+func TestConvertSQLPlaceholders(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		targetStyle string
+		sourceStyle string
+		expect      string
+		paramNames  []string
+	}{
+		{
+			name:        "empty",
+			text:        "",
+			targetStyle: SQLPlaceholderDollar,
+			expect:      "",
+		},
+		{
+			name:        "question to dollar",
+			text:        "SELECT * FROM a WHERE x = ? AND y = ?",
+			targetStyle: SQLPlaceholderDollar,
+			expect:      "SELECT * FROM a WHERE x = $1 AND y = $2",
+		},
+		{
+			name:        "dollar to question",
+			text:        "SELECT * FROM a WHERE x = $1 AND y = $2",
+			targetStyle: SQLPlaceholderQuestion,
+			expect:      "SELECT * FROM a WHERE x = ? AND y = ?",
+		},
+		{
+			name:        "colon to dollar",
+			text:        "SELECT * FROM a WHERE x = :id OR y = :name OR z = :id",
+			targetStyle: SQLPlaceholderDollar,
+			expect:      "SELECT * FROM a WHERE x = $1 OR y = $2 OR z = $1",
+			paramNames:  []string{"id", "name"},
+		},
+		{
+			name:        "colon to question",
+			text:        "SELECT * FROM a WHERE x = :id OR z = :id",
+			targetStyle: SQLPlaceholderQuestion,
+			expect:      "SELECT * FROM a WHERE x = ? OR z = ?",
+			paramNames:  []string{"id", "id"},
+		},
+		{
+			name:        "at to colon",
+			text:        "UPDATE a SET x = @x WHERE id = @id",
+			targetStyle: SQLPlaceholderColon,
+			expect:      "UPDATE a SET x = :x WHERE id = :id",
+			paramNames:  []string{"x", "id"},
+		},
+		{
+			name:        "same style",
+			text:        "SELECT :a, :b",
+			targetStyle: SQLPlaceholderColon,
+			expect:      "SELECT :a, :b",
+			paramNames:  []string{"a", "b"},
+		},
+		{
+			name:        "placeholders in quoted text and comments",
+			text:        "SELECT '?', \":x\" -- ?\n/* $1 */ FROM a WHERE x = ?",
+			targetStyle: SQLPlaceholderDollar,
+			expect:      "SELECT '?', \":x\" -- ?\n/* $1 */ FROM a WHERE x = $1",
+		},
+		{
+			name:        "source style skips other styles",
+			text:        "SET @v = 1; SELECT * FROM a WHERE x = :x",
+			targetStyle: SQLPlaceholderQuestion,
+			sourceStyle: SQLPlaceholderColon,
+			expect:      "SET @v = 1; SELECT * FROM a WHERE x = ?",
+			paramNames:  []string{"x"},
+		},
+		{
+			name:        "source style keeps question operator",
+			text:        "SELECT * FROM a WHERE doc ? 'k' AND id = :id",
+			targetStyle: SQLPlaceholderDollar,
+			sourceStyle: SQLPlaceholderColon,
+			expect:      "SELECT * FROM a WHERE doc ? 'k' AND id = $1",
+			paramNames:  []string{"id"},
+		},
+	}
+	for _, tt := range tests {
+		tokens, err := lexSQL(tt.text, false)
+		if nil != err {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		paramNames, err := convertSQLPlaceholders(tokens, tt.targetStyle, tt.sourceStyle)
+		if nil != err {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if result := joinSQLTokens(tokens); result != tt.expect {
+			t.Errorf("%s: expecting %q but having %q", tt.name, tt.expect, result)
+		}
+		if len(paramNames) != len(tt.paramNames) {
+			t.Errorf("%s: expecting parameter names %v but having %v", tt.name, tt.paramNames, paramNames)
+			continue
+		}
+		for idx, name := range paramNames {
+			if name != tt.paramNames[idx] {
+				t.Errorf("%s: expecting parameter names %v but having %v", tt.name, tt.paramNames, paramNames)
+				break
+			}
+		}
+	}
+}
+
+func TestConvertSQLPlaceholdersError(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		targetStyle string
+		sourceStyle string
+	}{
+		{"mixed styles", "SELECT ? , $2", SQLPlaceholderDollar, ""},
+		{"positional to named", "SELECT ?", SQLPlaceholderColon, ""},
+		{"dollar out of order to question", "SELECT $2, $1", SQLPlaceholderQuestion, ""},
+		{"unknown target style", "SELECT ?", "percent", ""},
+	}
+	for _, tt := range tests {
+		tokens, err := lexSQL(tt.text, false)
+		if nil != err {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if _, err = convertSQLPlaceholders(tokens, tt.targetStyle, tt.sourceStyle); nil == err {
+			t.Errorf("%s: expecting error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package literalcodegen
+
+import (
+	"strings"
+)
+
+func nextSQLSignificantToken(tokens []sqlToken, index int) *sqlToken {
+	for idx := index; idx < len(tokens); idx++ {
+		if tok := &tokens[idx]; (tok.Type != sqlTokenWhitespace) && !tok.isComment() {
+			return tok
+		}
+	}
+	return nil
+}
+
+func isSQLKeyword(tok *sqlToken, keywords ...string) bool {
+	if (nil == tok) || (tok.Type != sqlTokenWord) {
+		return false
+	}
+	for _, keyword := range keywords {
+		if strings.EqualFold(tok.Text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// sqlTransactionBeginFollowers are keywords following BEGIN when it starts a
+// transaction, including transaction modes of PostgreSQL (eg: `BEGIN
+// ISOLATION LEVEL SERIALIZABLE`, `BEGIN READ ONLY`, `BEGIN NOT DEFERRABLE`).
+var sqlTransactionBeginFollowers = []string{
+	"TRANSACTION", "TRAN", "WORK",
+	"DEFERRED", "IMMEDIATE", "EXCLUSIVE",
+	"ISOLATION", "READ", "NOT", "DEFERRABLE",
+}
+
+// isSQLBlockBegin checks if the BEGIN keyword at given index starts a
+// compound statement body instead of a transaction.
+func isSQLBlockBegin(tokens []sqlToken, index int) bool {
+	next := nextSQLSignificantToken(tokens, index+1)
+	if nil == next {
+		return false
+	}
+	if (next.Type == sqlTokenPunctuation) && (next.Text == ";") {
+		return false
+	}
+	return !isSQLKeyword(next, sqlTransactionBeginFollowers...)
+}
+
+// splitSQLStatements split tokens into statements with semicolons which are
+// not enclosed in BEGIN ... END or CASE ... END bodies. Quoted text and
+// comments are single tokens so semicolons in them never split statements.
+func splitSQLStatements(tokens []sqlToken) (statements [][]sqlToken) {
+	depth := 0
+	start := 0
+	for idx := range tokens {
+		tok := &tokens[idx]
+		switch {
+		case isSQLKeyword(tok, "BEGIN"):
+			if isSQLBlockBegin(tokens, idx) {
+				depth++
+			}
+		case isSQLKeyword(tok, "CASE"):
+			if !isSQLKeyword(lastSQLSignificantToken(tokens[:idx]), "END") {
+				depth++
+			}
+		case isSQLKeyword(tok, "END"):
+			if !isSQLKeyword(nextSQLSignificantToken(tokens, idx+1), "IF", "LOOP", "WHILE", "REPEAT", "FOR") && (depth > 0) {
+				depth--
+			}
+		case (tok.Type == sqlTokenPunctuation) && (tok.Text == ";") && (depth == 0):
+			statements = appendSQLStatement(statements, tokens[start:idx])
+			start = idx + 1
+		}
+	}
+	return appendSQLStatement(statements, tokens[start:])
+}
+
+func lastSQLSignificantToken(tokens []sqlToken) *sqlToken {
+	for idx := len(tokens) - 1; idx >= 0; idx-- {
+		if tok := &tokens[idx]; (tok.Type != sqlTokenWhitespace) && !tok.isComment() {
+			return tok
+		}
+	}
+	return nil
+}
+
+func appendSQLStatement(statements [][]sqlToken, tokens []sqlToken) [][]sqlToken {
+	for (len(tokens) > 0) && (tokens[0].Type == sqlTokenWhitespace) {
+		tokens = tokens[1:]
+	}
+	tokens = trimSQLTailTokens(tokens)
+	if nil == nextSQLSignificantToken(tokens, 0) {
+		return statements
+	}
+	return append(statements, tokens)
+}
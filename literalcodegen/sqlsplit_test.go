@@ -0,0 +1,188 @@
+package literalcodegen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitSQLStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		expect []string
+	}{
+		{
+			name:   "empty",
+			text:   "",
+			expect: nil,
+		},
+		{
+			name:   "comments only",
+			text:   "-- c1;\n/* c2; */\n",
+			expect: nil,
+		},
+		{
+			name:   "semicolons only",
+			text:   " ; ;\n",
+			expect: nil,
+		},
+		{
+			name:   "statements",
+			text:   "CREATE TABLE a (id INT);\nCREATE TABLE b (id INT);\n",
+			expect: []string{"CREATE TABLE a (id INT)", "CREATE TABLE b (id INT)"},
+		},
+		{
+			name:   "semicolons in quoted text and comments",
+			text:   "INSERT INTO a VALUES ('x;y', \"p;q\"); -- c;\nSELECT 1 /* ; */",
+			expect: []string{"INSERT INTO a VALUES ('x;y', \"p;q\")", "-- c;\nSELECT 1 /* ; */"},
+		},
+		{
+			name: "trigger body",
+			text: "CREATE TRIGGER t AFTER INSERT ON a BEGIN UPDATE b SET n = n + 1; DELETE FROM c; END;\nSELECT 1;",
+			expect: []string{
+				"CREATE TRIGGER t AFTER INSERT ON a BEGIN UPDATE b SET n = n + 1; DELETE FROM c; END",
+				"SELECT 1",
+			},
+		},
+		{
+			name: "case expression in block",
+			text: "CREATE PROCEDURE p() BEGIN SELECT CASE WHEN x THEN 1 ELSE 2 END; IF y THEN SELECT 3; END IF; END; SELECT 4",
+			expect: []string{
+				"CREATE PROCEDURE p() BEGIN SELECT CASE WHEN x THEN 1 ELSE 2 END; IF y THEN SELECT 3; END IF; END",
+				"SELECT 4",
+			},
+		},
+		{
+			name:   "begin",
+			text:   "BEGIN; SELECT 1; COMMIT;",
+			expect: []string{"BEGIN", "SELECT 1", "COMMIT"},
+		},
+		{
+			name:   "begin transaction",
+			text:   "BEGIN TRANSACTION; SELECT 1; COMMIT;",
+			expect: []string{"BEGIN TRANSACTION", "SELECT 1", "COMMIT"},
+		},
+		{
+			name:   "begin work",
+			text:   "begin work; SELECT 1; COMMIT;",
+			expect: []string{"begin work", "SELECT 1", "COMMIT"},
+		},
+		{
+			name:   "begin immediate",
+			text:   "BEGIN IMMEDIATE; SELECT 1; COMMIT;",
+			expect: []string{"BEGIN IMMEDIATE", "SELECT 1", "COMMIT"},
+		},
+		{
+			name:   "begin isolation level",
+			text:   "BEGIN ISOLATION LEVEL SERIALIZABLE; SELECT 1; COMMIT;",
+			expect: []string{"BEGIN ISOLATION LEVEL SERIALIZABLE", "SELECT 1", "COMMIT"},
+		},
+		{
+			name:   "begin read write",
+			text:   "BEGIN READ WRITE; SELECT 1; COMMIT;",
+			expect: []string{"BEGIN READ WRITE", "SELECT 1", "COMMIT"},
+		},
+		{
+			name:   "begin read only",
+			text:   "BEGIN READ ONLY; SELECT 1; COMMIT;",
+			expect: []string{"BEGIN READ ONLY", "SELECT 1", "COMMIT"},
+		},
+		{
+			name:   "begin not deferrable",
+			text:   "BEGIN NOT DEFERRABLE; SELECT 1; COMMIT;",
+			expect: []string{"BEGIN NOT DEFERRABLE", "SELECT 1", "COMMIT"},
+		},
+		{
+			name:   "begin with comment",
+			text:   "BEGIN /* tx */ ; SELECT 1; COMMIT",
+			expect: []string{"BEGIN /* tx */", "SELECT 1", "COMMIT"},
+		},
+	}
+	for _, tt := range tests {
+		tokens, err := lexSQL(tt.text, false)
+		if nil != err {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		statements := splitSQLStatements(tokens)
+		if len(statements) != len(tt.expect) {
+			t.Errorf("%s: expecting %d statements but having %d: %q", tt.name, len(tt.expect), len(statements), joinSQLStatements(statements))
+			continue
+		}
+		for idx, stmt := range statements {
+			if text := joinSQLTokens(stmt); text != tt.expect[idx] {
+				t.Errorf("%s: statement %d: expecting %q but having %q", tt.name, idx, tt.expect[idx], text)
+			}
+		}
+	}
+}
+
+func joinSQLStatements(statements [][]sqlToken) (result []string) {
+	for _, stmt := range statements {
+		result = append(result, joinSQLTokens(stmt))
+	}
+	return
+}
+
+func TestSQLFilterSplitPlaceholders(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    []string
+		filterArgs []string
+		expect     []string
+		paramNames [][]string
+	}{
+		{
+			name:       "positional placeholders are numbered per statement",
+			content:    []string{"INSERT INTO a VALUES (?); INSERT INTO b VALUES (?, ?);"},
+			filterArgs: []string{"split-statements", "placeholder=dollar"},
+			expect:     []string{"INSERT INTO a VALUES ($1)", "INSERT INTO b VALUES ($1,$2)"},
+			paramNames: [][]string{nil, nil},
+		},
+		{
+			name:       "named placeholders have name list per statement",
+			content:    []string{"INSERT INTO a VALUES (:x);", "DELETE FROM c;", "INSERT INTO b VALUES (:y, :x);"},
+			filterArgs: []string{"split-statements", "placeholder=question"},
+			expect:     []string{"INSERT INTO a VALUES (?)", "DELETE FROM c", "INSERT INTO b VALUES (?,?)"},
+			paramNames: [][]string{{"x"}, nil, {"y", "x"}},
+		},
+		{
+			name:       "comments only",
+			content:    []string{"-- INSERT INTO a VALUES (?);"},
+			filterArgs: []string{"split-statements", "placeholder=dollar"},
+			expect:     nil,
+			paramNames: nil,
+		},
+	}
+	filter := &sqlLanguageFilter{}
+	for _, tt := range tests {
+		pieces, err := filter.SplitContent(tt.content, LanguageFilterArgs(tt.filterArgs))
+		if nil != err {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if len(pieces) != len(tt.expect) {
+			t.Errorf("%s: expecting %d pieces but having %d: %q", tt.name, len(tt.expect), len(pieces), pieces)
+			continue
+		}
+		for idx, piece := range pieces {
+			if text := strings.Join(piece, "\n"); text != tt.expect[idx] {
+				t.Errorf("%s: piece %d: expecting %q but having %q", tt.name, idx, tt.expect[idx], text)
+			}
+		}
+		_, _, paramNames, _, err := filterSQLTokens(tt.content, LanguageFilterArgs(tt.filterArgs))
+		if nil != err {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if len(paramNames) != len(tt.paramNames) {
+			t.Errorf("%s: expecting parameter names %v but having %v", tt.name, tt.paramNames, paramNames)
+			continue
+		}
+		for idx, names := range paramNames {
+			if strings.Join(names, ",") != strings.Join(tt.paramNames[idx], ",") {
+				t.Errorf("%s: statement %d: expecting parameter names %v but having %v", tt.name, idx, tt.paramNames[idx], names)
+			}
+		}
+	}
+}
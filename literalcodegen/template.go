@@ -0,0 +1,136 @@
+package literalcodegen
+
+import (
+	htmltemplate "html/template"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Fence languages of Go templates.
+const (
+	LanguageTextTemplate = "tmpl"
+	LanguageHTMLTemplate = "gohtml"
+)
+
+// TemplateFunc is a function to be registered into template FuncMap.
+type TemplateFunc struct {
+	Name string
+	Code string
+}
+
+func (entry *LiteralEntry) appendTemplateFuncText(v string) {
+	lastIndex := len(entry.TemplateFuncs) - 1
+	if (lastIndex >= 0) && (entry.TemplateFuncs[lastIndex].Code == "") {
+		entry.TemplateFuncs[lastIndex].Code = v
+		return
+	}
+	entry.TemplateFuncs = append(entry.TemplateFuncs, &TemplateFunc{
+		Name: v,
+	})
+}
+
+func (entry *LiteralEntry) appendTemplateDelimText(v string) {
+	if entry.TemplateLeftDelim == "" {
+		entry.TemplateLeftDelim = v
+	} else {
+		entry.TemplateRightDelim = v
+	}
+}
+
+func (entry *LiteralEntry) isTemplateContent() bool {
+	return (entry.LanguageType == LanguageTextTemplate) || (entry.LanguageType == LanguageHTMLTemplate)
+}
+
+func templateValidateFuncMap(funcs []*TemplateFunc) map[string]interface{} {
+	funcMap := make(map[string]interface{})
+	for _, f := range funcs {
+		funcMap[f.Name] = func(...interface{}) interface{} { return nil }
+	}
+	return funcMap
+}
+
+func (entry *LiteralEntry) parseTemplateText(text string) (err error) {
+	if entry.LanguageType == LanguageHTMLTemplate {
+		_, err = htmltemplate.New(entry.Name).Delims(entry.TemplateLeftDelim, entry.TemplateRightDelim).Funcs(templateValidateFuncMap(entry.TemplateFuncs)).Parse(text)
+	} else {
+		_, err = template.New(entry.Name).Delims(entry.TemplateLeftDelim, entry.TemplateRightDelim).Funcs(templateValidateFuncMap(entry.TemplateFuncs)).Parse(text)
+	}
+	return
+}
+
+var templateErrorLineTrap = regexp.MustCompile(`^template: [^:]*:(\d+):\s*`)
+
+// templateContentError locate error of template with new line separated
+// content so that line number can be mapped to Markdown input.
+func (entry *LiteralEntry) templateContentError(content []string, err error) error {
+	text, _ := joinContentLines(content)
+	lineErr := entry.parseTemplateText(text)
+	if nil == lineErr {
+		return &ContentPositionError{Err: err}
+	}
+	msg := lineErr.Error()
+	m := templateErrorLineTrap.FindStringSubmatchIndex(msg)
+	if nil == m {
+		return &ContentPositionError{Err: lineErr}
+	}
+	line, convErr := strconv.ParseInt(msg[m[2]:m[3]], 10, 31)
+	if nil != convErr {
+		return &ContentPositionError{Err: lineErr}
+	}
+	return &ContentPositionError{
+		LineIndex: int(line) - 1,
+		Err:       &templateParseError{msg[m[1]:]},
+	}
+}
+
+type templateParseError struct {
+	msg string
+}
+
+func (e *templateParseError) Error() string {
+	return "template parse failed: " + e.msg
+}
+
+// templateText return filtered content as template text.
+// The template is parsed to make sure it is valid.
+func (entry *LiteralEntry) templateText() (text string, err error) {
+	content, err := entry.FilteredContent()
+	if nil != err {
+		return
+	}
+	text = strings.Join(content, "")
+	if err = entry.parseTemplateText(text); nil != err {
+		return "", entry.languageFilterError(entry.templateContentError(content, err))
+	}
+	return text, nil
+}
+
+func generateLiteralCodeAsTemplate(fp *os.File, entry *LiteralEntry) (err error) {
+	text, err := entry.templateText()
+	if nil != err {
+		return
+	}
+	codeLine := "var " + entry.Name + " = template.Must(template.New(" + strconv.Quote(entry.Name) + ")"
+	if (entry.TemplateLeftDelim != "") || (entry.TemplateRightDelim != "") {
+		codeLine = codeLine + ".Delims(" + strconv.Quote(entry.TemplateLeftDelim) + ", " + strconv.Quote(entry.TemplateRightDelim) + ")"
+	}
+	if len(entry.TemplateFuncs) > 0 {
+		codeLine = codeLine + ".Funcs(template.FuncMap{\n"
+		for _, f := range entry.TemplateFuncs {
+			codeLine = codeLine + "\t" + strconv.Quote(f.Name) + ": " + f.Code + ",\n"
+		}
+		codeLine = codeLine + "})"
+	}
+	codeLine = codeLine + ".Parse("
+	if _, err = fp.WriteString(codeLine); nil != err {
+		return
+	}
+	if err = writeLiteralContent(fp, []string{text}, "))"); nil != err {
+		return
+	}
+	_, err = fp.WriteString("\n")
+	return
+}
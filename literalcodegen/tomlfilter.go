@@ -0,0 +1,51 @@
+package literalcodegen
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+func init() {
+	RegisterLanguageFilter("toml", LanguageFilterFunc(tomlContentFilter))
+}
+
+var tomlErrorPrefixTrap = regexp.MustCompile(`^toml: line \d+(?: \(last key "[^"]*"\))?: `)
+
+func newTOMLContentError(text string, err error) error {
+	parseErr, ok := err.(toml.ParseError)
+	if !ok {
+		return fmt.Errorf("invalid TOML: %v", err)
+	}
+	posErr := &ContentPositionError{
+		LineIndex: parseErr.Position.Line - 1,
+		Err:       fmt.Errorf("invalid TOML: %s", tomlErrorPrefixTrap.ReplaceAllString(parseErr.Error(), "")),
+	}
+	if (parseErr.Position.Len > 0) && (parseErr.Position.Start <= len(text)) {
+		prefix := text[:parseErr.Position.Start]
+		posErr.LineIndex = strings.Count(prefix, "\n")
+		posErr.Column = len(prefix) - strings.LastIndexByte(prefix, '\n')
+	}
+	return posErr
+}
+
+func tomlContentFilter(codeContent []string, filterArgs LanguageFilterArgs) (result []string, err error) {
+	text, newLineMode := joinContentLines(codeContent)
+	var v map[string]interface{}
+	if _, err = toml.Decode(text, &v); nil != err {
+		return nil, newTOMLContentError(text, err)
+	}
+	if parseStructuredOutputMode(filterArgs) == structuredOutputVerbatim {
+		return newLineMode.restore(splitLinesWithNewLine(text)), nil
+	}
+	var buf bytes.Buffer
+	encoder := toml.NewEncoder(&buf)
+	encoder.Indent = ""
+	if err = encoder.Encode(v); nil != err {
+		return
+	}
+	return newLineMode.restore(splitLinesWithNewLine(strings.TrimSuffix(buf.String(), "\n"))), nil
+}
@@ -0,0 +1,76 @@
+package literalcodegen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+)
+
+func TestTOMLContentFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    []string
+		filterArgs []string
+		expect     string
+	}{
+		{
+			name:    "verbatim without new line option",
+			content: []string{"a = 1", "[b]", "c = 2 # note"},
+			expect:  "a = 1\n[b]\nc = 2 # note",
+		},
+		{
+			name:    "verbatim with new line on each line",
+			content: []string{"a = 1\n", "b = \"x\"\n"},
+			expect:  "a = 1\nb = \"x\"\n",
+		},
+		{
+			name:       "canonical",
+			content:    []string{"z = 1", "a = 2 # note"},
+			filterArgs: []string{"canonical"},
+			expect:     "a = 2\nz = 1",
+		},
+	}
+	for _, tt := range tests {
+		result, err := tomlContentFilter(tt.content, LanguageFilterArgs(tt.filterArgs))
+		if nil != err {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		text := strings.Join(result, "")
+		if text != tt.expect {
+			t.Errorf("%s: expecting %q but having %q", tt.name, tt.expect, text)
+			continue
+		}
+		var v map[string]interface{}
+		if _, err = toml.Decode(text, &v); nil != err {
+			t.Errorf("%s: output is not valid TOML: %v", tt.name, err)
+		}
+	}
+}
+
+func TestTOMLContentFilterError(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   []string
+		lineIndex int
+	}{
+		{"missing value", []string{"a = 1", "b ="}, 1},
+		{"duplicated key", []string{"a = 1", "a = 2"}, 1},
+	}
+	for _, tt := range tests {
+		_, err := tomlContentFilter(tt.content, nil)
+		if nil == err {
+			t.Errorf("%s: expecting error", tt.name)
+			continue
+		}
+		posErr, ok := err.(*ContentPositionError)
+		if !ok {
+			t.Errorf("%s: expecting position error but having %T: %v", tt.name, err, err)
+			continue
+		}
+		if posErr.LineIndex != tt.lineIndex {
+			t.Errorf("%s: expecting error at line %d but having %d: %v", tt.name, tt.lineIndex, posErr.LineIndex, err)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package literalcodegen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TextTypedReplacementPrefix is the leading character of replacement code which
+// refers to a builder parameter. Conversion code is generated according to
+// declared type of the parameter.
+const TextTypedReplacementPrefix = "@"
+
+// DefaultTimeLayout is the layout expression for converting time.Time parameters.
+const DefaultTimeLayout = "time.RFC3339"
+
+func parseParameterTypes(params []string) (paramTypes map[string]string) {
+	paramTypes = make(map[string]string)
+	for _, param := range params {
+		param = strings.TrimSpace(param)
+		idx := strings.LastIndexAny(param, " \t")
+		if idx < 0 {
+			continue
+		}
+		typeText := strings.TrimSpace(param[idx+1:])
+		for _, name := range strings.Split(param[:idx], ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				paramTypes[name] = typeText
+			}
+		}
+	}
+	return
+}
+
+func (entry *LiteralEntry) isStringerType(typeText string) bool {
+	if typeText == "fmt.Stringer" {
+		return true
+	}
+	for _, t := range entry.StringerTypes {
+		if t == typeText {
+			return true
+		}
+	}
+	return false
+}
+
+func (entry *LiteralEntry) timeLayout() string {
+	if entry.TimeLayout == "" {
+		return DefaultTimeLayout
+	}
+	return entry.TimeLayout
+}
+
+func (entry *LiteralEntry) typedConversionCode(name, typeText string) (code string, err error) {
+	switch typeText {
+	case "string":
+		return name, nil
+	case "[]byte":
+		return "string(" + name + ")", nil
+	case "int":
+		return "strconv.Itoa(" + name + ")", nil
+	case "int8", "int16", "int32":
+		return "strconv.FormatInt(int64(" + name + "), 10)", nil
+	case "int64":
+		return "strconv.FormatInt(" + name + ", 10)", nil
+	case "uint", "uint8", "uint16", "uint32":
+		return "strconv.FormatUint(uint64(" + name + "), 10)", nil
+	case "uint64":
+		return "strconv.FormatUint(" + name + ", 10)", nil
+	case "float32":
+		return "strconv.FormatFloat(float64(" + name + "), 'g', -1, 32)", nil
+	case "float64":
+		return "strconv.FormatFloat(" + name + ", 'g', -1, 64)", nil
+	case "bool":
+		return "strconv.FormatBool(" + name + ")", nil
+	case "time.Time":
+		return name + ".Format(" + entry.timeLayout() + ")", nil
+	}
+	if entry.isStringerType(typeText) {
+		return name + ".String()", nil
+	}
+	return "", fmt.Errorf("unsupported type for typed replacement of parameter %s: %s (entry: %v)", name, typeText, entry.TitleText)
+}
+
+func (entry *LiteralEntry) resolveReplacementCode(paramTypes map[string]string, replacementCode string) (code string, err error) {
+	if !strings.HasPrefix(replacementCode, TextTypedReplacementPrefix) {
+		return replacementCode, nil
+	}
+	name := strings.TrimSpace(strings.TrimPrefix(replacementCode, TextTypedReplacementPrefix))
+	typeText, ok := paramTypes[name]
+	if !ok {
+		return "", fmt.Errorf("typed replacement refers to unknown builder parameter: %s (entry: %v)", name, entry.TitleText)
+	}
+	return entry.typedConversionCode(name, typeText)
+}
+
+func (entry *LiteralEntry) resolveReplaceResults(paramTypes map[string]string, results []*ReplaceResult) (err error) {
+	for _, result := range results {
+		if result.ReplacedCode, err = entry.resolveReplacementCode(paramTypes, result.ReplacedCode); nil != err {
+			return
+		}
+		result.ReplacedCode = wrapEscaperCode(result.Escaper, result.ReplacedCode)
+	}
+	return nil
+}
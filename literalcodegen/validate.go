@@ -0,0 +1,141 @@
+package literalcodegen
+
+import (
+	"fmt"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"strings"
+)
+
+// ErrGoCodeValidation collects errors found on validating Go code pieces
+// which will be placed into generated code.
+type ErrGoCodeValidation struct {
+	Errors []error
+}
+
+func (err *ErrGoCodeValidation) Error() string {
+	aux := make([]string, 0, len(err.Errors))
+	for _, e := range err.Errors {
+		aux = append(aux, e.Error())
+	}
+	return fmt.Sprintf("found %d error(s) on validating literal entries:\n%s", len(err.Errors), strings.Join(aux, "\n"))
+}
+
+func (err *ErrGoCodeValidation) append(e error) {
+	err.Errors = append(err.Errors, e)
+}
+
+func validateReplacementCode(entry *LiteralEntry, result *ReplaceResult, contentLineIndex int) (err error) {
+	if result.ReplacedCode == "" {
+		return nil
+	}
+	if _, err = parser.ParseExpr(result.ReplacedCode); nil == err {
+		return nil
+	}
+	location := result.Location
+	if location.IsZero() {
+		location = entry.ContentLineLocation(contentLineIndex)
+	}
+	return fmt.Errorf("%v: invalid replacement code %q of entry [%s]: %v", location, result.ReplacedCode, entry.TitleText, err)
+}
+
+func validateBuilderReplacements(entry *LiteralEntry, errs *ErrGoCodeValidation) {
+	content, err := entry.FilteredContent()
+	if nil != err {
+		errs.append(err)
+		return
+	}
+	paramTypes := parseParameterTypes(entry.Parameters)
+	for idx, line := range content {
+		replaced, err := doReplace(entry.replaceRules, line)
+		if nil != err {
+			errs.append(fmt.Errorf("%v: %v", entry.ContentLineLocation(idx), err))
+			continue
+		}
+		if err = entry.resolveReplaceResults(paramTypes, replaced); nil != err {
+			errs.append(fmt.Errorf("%v: %v", entry.ContentLineLocation(idx), err))
+			continue
+		}
+		for _, result := range replaced {
+			if err = validateReplacementCode(entry, result, idx); nil != err {
+				errs.append(err)
+			}
+		}
+	}
+}
+
+func validateBuilderPrepare(entry *LiteralEntry, errs *ErrGoCodeValidation) {
+	prepare := entry.BuilderPrepare
+	if (nil == prepare) || (len(prepare.Content) == 0) {
+		return
+	}
+	const wrapperHeadingLines = 2
+	var b strings.Builder
+	b.WriteString("package p\nfunc _() {\n")
+	for _, line := range prepare.Content {
+		b.WriteString(strings.TrimRight(line, "\n"))
+		b.WriteString("\n")
+	}
+	b.WriteString("}\n")
+	fset := token.NewFileSet()
+	_, err := parser.ParseFile(fset, "", b.String(), 0)
+	if nil == err {
+		return
+	}
+	errList, ok := err.(scanner.ErrorList)
+	if (!ok) || (len(errList) == 0) {
+		errs.append(fmt.Errorf("%v: invalid builder prepare code of entry [%s]: %v", prepare.ContentLocation, entry.TitleText, err))
+		return
+	}
+	// only the first error is reported since the following errors are usually caused by the first one
+	e := errList[0]
+	location := prepare.ContentLineLocation(e.Pos.Line - wrapperHeadingLines - 1)
+	errs.append(fmt.Errorf("%v: invalid builder prepare code of entry [%s]: %v", location, entry.TitleText, e.Msg))
+}
+
+func validateTemplateFuncs(entry *LiteralEntry, errs *ErrGoCodeValidation) {
+	for _, f := range entry.TemplateFuncs {
+		if _, err := parser.ParseExpr(f.Code); nil != err {
+			errs.append(fmt.Errorf("%v: invalid code of template function %s of entry [%s]: %v", entry.TitleLocation, f.Name, entry.TitleText, err))
+		}
+	}
+}
+
+// ValidateGoCode checks Go code pieces which are pasted into generated code
+// including replacement codes and builder prepare blocks.
+func ValidateGoCode(entries []*LiteralEntry) (err error) {
+	errs := &ErrGoCodeValidation{}
+	for _, entry := range entries {
+		if (entry.Name == "") || (entry.Name == "-") {
+			continue
+		}
+		switch entry.TranslationMode {
+		case TranslateAsConst:
+			if _, err = entry.FilteredContent(); nil != err {
+				errs.append(err)
+			}
+		case TranslateAsBuilder:
+			validateBuilderReplacements(entry, errs)
+			validateBuilderPrepare(entry, errs)
+		case TranslateAsRegexp:
+			if _, err = entry.regexpPattern(); nil != err {
+				errs.append(err)
+			}
+		case TranslateAsTemplate:
+			if _, err = entry.templateText(); nil != err {
+				errs.append(err)
+			}
+			validateTemplateFuncs(entry, errs)
+		}
+		if (entry.TranslationMode != TranslateAsTemplate) && entry.isTemplateContent() {
+			if _, err = entry.templateText(); nil != err {
+				errs.append(err)
+			}
+		}
+	}
+	if len(errs.Errors) > 0 {
+		return errs
+	}
+	return nil
+}
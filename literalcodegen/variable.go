@@ -0,0 +1,104 @@
+package literalcodegen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TextVariablePlaceholderPrefix is the leading text of generation-time variable placeholder.
+// Placeholder is in the form of `${var:NAME}`.
+const TextVariablePlaceholderPrefix = "${var:"
+
+// TextVariablePlaceholderSuffix is the tailing text of generation-time variable placeholder.
+const TextVariablePlaceholderSuffix = "}"
+
+// ErrMissingVariables indicates placeholders referencing undefined variables are found.
+type ErrMissingVariables struct {
+	TitleText string
+	Names     []string
+}
+
+func (err *ErrMissingVariables) Error() string {
+	return fmt.Sprintf("undefined generation-time variable(s) in entry [%s]: %s", err.TitleText, strings.Join(err.Names, ", "))
+}
+
+func expandVariablesInLine(line string, variables map[string]string, missing map[string]struct{}) string {
+	if !strings.Contains(line, TextVariablePlaceholderPrefix) {
+		return line
+	}
+	var b strings.Builder
+	remain := line
+	for {
+		startIdx := strings.Index(remain, TextVariablePlaceholderPrefix)
+		if startIdx < 0 {
+			break
+		}
+		nameStart := startIdx + len(TextVariablePlaceholderPrefix)
+		nameLength := strings.Index(remain[nameStart:], TextVariablePlaceholderSuffix)
+		if nameLength < 0 {
+			break
+		}
+		name := strings.TrimSpace(remain[nameStart : nameStart+nameLength])
+		b.WriteString(remain[:startIdx])
+		if value, ok := variables[name]; ok {
+			b.WriteString(value)
+		} else {
+			missing[name] = struct{}{}
+			b.WriteString(remain[startIdx : nameStart+nameLength+len(TextVariablePlaceholderSuffix)])
+		}
+		remain = remain[nameStart+nameLength+len(TextVariablePlaceholderSuffix):]
+	}
+	b.WriteString(remain)
+	return b.String()
+}
+
+func (entry *LiteralEntry) expandVariables(variables map[string]string) (err error) {
+	missing := make(map[string]struct{})
+	for idx, line := range entry.Content {
+		entry.Content[idx] = expandVariablesInLine(line, variables, missing)
+	}
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return &ErrMissingVariables{
+			TitleText: entry.TitleText,
+			Names:     names,
+		}
+	}
+	if nil != entry.BuilderPrepare {
+		return entry.BuilderPrepare.expandVariables(variables)
+	}
+	return nil
+}
+
+// SetVariable define or override a generation-time variable.
+func (l *LiteralCode) SetVariable(name, value string) {
+	if nil == l.Variables {
+		l.Variables = make(map[string]string)
+	}
+	l.Variables[name] = value
+}
+
+// ExpandVariables replace variable placeholders in content of entries with
+// values of generation-time variables. Values in overrides take precedence
+// over variables defined in Markdown.
+func (l *LiteralCode) ExpandVariables(overrides map[string]string) (err error) {
+	for name, value := range overrides {
+		l.SetVariable(name, value)
+	}
+	for _, entry := range l.HeadingCodes {
+		if err = entry.expandVariables(l.Variables); nil != err {
+			return
+		}
+	}
+	for _, entry := range l.LiteralConstants {
+		if err = entry.expandVariables(l.Variables); nil != err {
+			return
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package literalcodegen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	RegisterLanguageFilter("yaml", LanguageFilterFunc(yamlContentFilter))
+	RegisterLanguageFilter("yml", LanguageFilterFunc(yamlContentFilter))
+}
+
+// Output modes of structured data (YAML and TOML) filters.
+const (
+	structuredOutputVerbatim  = "verbatim"
+	structuredOutputCanonical = "canonical"
+)
+
+func parseStructuredOutputMode(filterArgs LanguageFilterArgs) string {
+	return filterArgs.Choice(structuredOutputVerbatim, structuredOutputVerbatim, structuredOutputCanonical)
+}
+
+var yamlErrorLineTrap = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// newYAMLContentError map line number in YAML error message to content position.
+func newYAMLContentError(err error) error {
+	messages := []string{err.Error()}
+	if typeErr, ok := err.(*yaml.TypeError); ok {
+		messages = typeErr.Errors
+	}
+	for _, msg := range messages {
+		m := yamlErrorLineTrap.FindStringSubmatch(strings.TrimSpace(msg))
+		if nil == m {
+			continue
+		}
+		lineNumber, _ := strconv.Atoi(m[1])
+		return &ContentPositionError{
+			LineIndex: lineNumber - 1,
+			Err:       fmt.Errorf("invalid YAML: %s", m[2]),
+		}
+	}
+	return fmt.Errorf("invalid YAML: %v", err)
+}
+
+// parseYAMLDocuments parse every document in given text. Documents are also
+// decoded to find out duplicated keys.
+func parseYAMLDocuments(text string) (docs []*yaml.Node, err error) {
+	decoder := yaml.NewDecoder(strings.NewReader(text))
+	for {
+		var doc yaml.Node
+		if err = decoder.Decode(&doc); nil != err {
+			if err == io.EOF {
+				return docs, nil
+			}
+			return nil, newYAMLContentError(err)
+		}
+		var v interface{}
+		if err = doc.Decode(&v); nil != err {
+			return nil, newYAMLContentError(err)
+		}
+		docs = append(docs, &doc)
+	}
+}
+
+func canonicalYAML(docs []*yaml.Node) (result string, err error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	for _, doc := range docs {
+		if err = encoder.Encode(doc); nil != err {
+			return
+		}
+	}
+	if err = encoder.Close(); nil != err {
+		return
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+func yamlContentFilter(codeContent []string, filterArgs LanguageFilterArgs) (result []string, err error) {
+	text, newLineMode := joinContentLines(codeContent)
+	docs, err := parseYAMLDocuments(text)
+	if nil != err {
+		return
+	}
+	if parseStructuredOutputMode(filterArgs) == structuredOutputCanonical {
+		if text, err = canonicalYAML(docs); nil != err {
+			return
+		}
+	}
+	return newLineMode.restore(splitLinesWithNewLine(text)), nil
+}
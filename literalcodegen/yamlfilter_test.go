@@ -0,0 +1,84 @@
+package literalcodegen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestYAMLContentFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    []string
+		filterArgs []string
+		expect     string
+	}{
+		{
+			name:    "verbatim without new line option",
+			content: []string{"a: 1", "b:", "  c: 2 # note"},
+			expect:  "a: 1\nb:\n  c: 2 # note",
+		},
+		{
+			name:    "verbatim with new line on each line",
+			content: []string{"a: 1\n", "b: [1, 2]\n"},
+			expect:  "a: 1\nb: [1, 2]\n",
+		},
+		{
+			name:    "verbatim with tail new line",
+			content: []string{"a: 1", "b: 2\n"},
+			expect:  "a: 1\nb: 2\n",
+		},
+		{
+			name:    "multiple documents",
+			content: []string{"a: 1", "---", "b: 2"},
+			expect:  "a: 1\n---\nb: 2",
+		},
+		{
+			name:       "canonical",
+			content:    []string{"a:   1", "b:", "    c: [1,   2]"},
+			filterArgs: []string{"canonical"},
+			expect:     "a: 1\nb:\n  c: [1, 2]",
+		},
+	}
+	for _, tt := range tests {
+		result, err := yamlContentFilter(tt.content, LanguageFilterArgs(tt.filterArgs))
+		if nil != err {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		text := strings.Join(result, "")
+		if text != tt.expect {
+			t.Errorf("%s: expecting %q but having %q", tt.name, tt.expect, text)
+			continue
+		}
+		if _, err = parseYAMLDocuments(text); nil != err {
+			t.Errorf("%s: output is not valid YAML: %v", tt.name, err)
+		}
+	}
+}
+
+func TestYAMLContentFilterError(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   []string
+		lineIndex int
+	}{
+		{"bad indent", []string{"a: 1", "  b: 2"}, 1},
+		{"duplicated key", []string{"a: 1", "a: 2"}, 1},
+		{"unterminated flow", []string{"a: [1, 2", "b: 3"}, 0},
+	}
+	for _, tt := range tests {
+		_, err := yamlContentFilter(tt.content, nil)
+		if nil == err {
+			t.Errorf("%s: expecting error", tt.name)
+			continue
+		}
+		posErr, ok := err.(*ContentPositionError)
+		if !ok {
+			t.Errorf("%s: expecting position error but having %T: %v", tt.name, err, err)
+			continue
+		}
+		if posErr.LineIndex != tt.lineIndex {
+			t.Errorf("%s: expecting error at line %d but having %d: %v", tt.name, tt.lineIndex, posErr.LineIndex, err)
+		}
+	}
+}
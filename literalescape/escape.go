@@ -0,0 +1,33 @@
+// Package literalescape provides escaping functions used by builders
+// generated from replace targets with escaper.
+package literalescape
+
+import (
+	"html"
+	"strings"
+)
+
+// QuotePostgreSQLIdentifier quotes given text as PostgreSQL identifier.
+func QuotePostgreSQLIdentifier(v string) string {
+	return "\"" + strings.Replace(v, "\"", "\"\"", -1) + "\""
+}
+
+// QuoteMySQLIdentifier quotes given text as MySQL identifier.
+func QuoteMySQLIdentifier(v string) string {
+	return "`" + strings.Replace(v, "`", "``", -1) + "`"
+}
+
+// QuoteSQLString quotes given text as standard SQL string literal.
+func QuoteSQLString(v string) string {
+	return "'" + strings.Replace(v, "'", "''", -1) + "'"
+}
+
+// EscapeHTML escapes special characters of HTML in given text.
+func EscapeHTML(v string) string {
+	return html.EscapeString(v)
+}
+
+// QuoteShell quotes given text as single argument of POSIX shell.
+func QuoteShell(v string) string {
+	return "'" + strings.Replace(v, "'", "'\\''", -1) + "'"
+}
@@ -0,0 +1,33 @@
+package literalescape
+
+import (
+	"testing"
+)
+
+func TestEscapers(t *testing.T) {
+	tests := []struct {
+		name    string
+		escaper func(string) string
+		input   string
+		expect  string
+	}{
+		{"postgresql identifier", QuotePostgreSQLIdentifier, "user", "\"user\""},
+		{"postgresql identifier with quote", QuotePostgreSQLIdentifier, "a\"b", "\"a\"\"b\""},
+		{"postgresql identifier empty", QuotePostgreSQLIdentifier, "", "\"\""},
+		{"mysql identifier", QuoteMySQLIdentifier, "user", "`user`"},
+		{"mysql identifier with backtick", QuoteMySQLIdentifier, "a`b", "`a``b`"},
+		{"sql string", QuoteSQLString, "abc", "'abc'"},
+		{"sql string with quote", QuoteSQLString, "it's", "'it''s'"},
+		{"sql string empty", QuoteSQLString, "", "''"},
+		{"html", EscapeHTML, "<a href=\"x\">&'</a>", "&lt;a href=&#34;x&#34;&gt;&amp;&#39;&lt;/a&gt;"},
+		{"html plain", EscapeHTML, "abc", "abc"},
+		{"shell", QuoteShell, "a b", "'a b'"},
+		{"shell with quote", QuoteShell, "it's", "'it'\\''s'"},
+		{"shell empty", QuoteShell, "", "''"},
+	}
+	for _, tt := range tests {
+		if result := tt.escaper(tt.input); result != tt.expect {
+			t.Errorf("%s: expecting %q but having %q", tt.name, tt.expect, result)
+		}
+	}
+}
@@ -9,7 +9,7 @@ import (
 )
 
 func main() {
-	inputFilePath, outputFilePath, genDoNotEdit, externalFilter, err := parseCommandParam()
+	inputFilePath, outputFilePath, genDoNotEdit, externalFilter, variables, err := parseCommandParam()
 	if nil != err {
 		log.Fatalf("ERR: cannot have required parameters: %v", err)
 		return
@@ -23,6 +23,10 @@ func main() {
 		return
 	}
 	log.Printf("** Loaded input.")
+	if err = code.ExpandVariables(variables); nil != err {
+		log.Fatalf("ERR: expanding generation-time variables failed: %v", err)
+		return
+	}
 	literalcodegen.LogLiteralCode(code)
 	log.Printf("** Going to generate code.")
 	err = literalcodegen.GenerateGoCodeFile(outputFilePath, code, genDoNotEdit, externalFilter)